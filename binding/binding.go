@@ -10,18 +10,49 @@ var (
 		contract.HttpMimeJson:          JSON,
 		contract.HttpMimeForm:          Form,
 		contract.HttpMimeMultipartForm: MultipartForm,
+		contract.HttpMimeXml:           XML,
+		contract.HttpMimeXml2:          XML,
+		contract.HttpMimeYaml:          YAML,
+		contract.HttpMimeYaml2:         YAML,
+		contract.HttpMimeMsgPack:       MsgPack,
+		contract.HttpMimeMsgPack2:      MsgPack,
+		contract.HttpMimeProtobuf:      Protobuf,
 	}
 )
 
+// Register the binding of the content type, the registered types are replaced
+func Register(contentType string, binding contract.Binding) {
+	httpBindingType[contentType] = binding
+}
+
+// Bind the http query, body and route parameters in turn, so that the route parameters take precedence over the body
+// and the body takes precedence over the query
 func Bind(protocol contract.Protocol, v interface{}) error {
-	if p, ok := protocol.(contract.HttpProtocol); ok {
-		contentType := p.ContentType()
+	p, ok := protocol.(contract.HttpProtocol)
+	if !ok {
+		// Protocols without content type, such as websocket, bind the JSON message
+		return JSON.Protocol(protocol, v)
+	}
 
-		if b, ok := httpBindingType[contentType]; ok {
-			return b.Protocol(protocol, v)
+	if p.Request().URL.RawQuery != `` {
+		if err := Query.Protocol(protocol, v); err != nil {
+			return err
 		}
+	}
+
+	if contentType := p.ContentType(); contentType != `` {
+		b, ok := httpBindingType[contentType]
+		if !ok {
+			return fmt.Errorf("non-existent type %s", contentType)
+		}
+
+		if err := b.Protocol(protocol, v); err != nil {
+			return err
+		}
+	}
 
-		return fmt.Errorf("non-existent type %s", contentType)
+	if len(p.Params()) > 0 {
+		return URI.Protocol(protocol, v)
 	}
 
 	return nil
@@ -0,0 +1,219 @@
+package binding
+
+import (
+	"bytes"
+	"errors"
+	"github.com/firmeve/firmeve/kernel/contract"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/stretchr/testify/assert"
+	msgpack2 "github.com/vmihailenco/msgpack/v4"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type (
+	mockHttpProtocol struct {
+		contract.HttpProtocol
+		request *http.Request
+		params  map[string]string
+	}
+
+	mockUser struct {
+		ID    int    `form:"id" json:"id" uri:"id"`
+		Name  string `form:"name" json:"name"`
+		Page  int    `form:"page"`
+		Token string `header:"X-Token"`
+		Agent string `header:"user-agent"`
+	}
+
+	mockEncodedUser struct {
+		ID   int    `xml:"id" yaml:"id" msgpack:"id"`
+		Name string `xml:"name" yaml:"name" msgpack:"name"`
+	}
+)
+
+func (m *mockHttpProtocol) Request() *http.Request {
+	return m.request
+}
+
+func (m *mockHttpProtocol) Params() map[string]string {
+	return m.params
+}
+
+func (m *mockHttpProtocol) ContentType() string {
+	return strings.Split(m.request.Header.Get(`Content-Type`), `;`)[0]
+}
+
+func (m *mockHttpProtocol) Message() ([]byte, error) {
+	return ioutil.ReadAll(m.request.Body)
+}
+
+func (m *mockHttpProtocol) Values() map[string][]string {
+	m.request.ParseMultipartForm(32 << 20)
+	return m.request.Form
+}
+
+func (m *mockHttpProtocol) ParseForm() error {
+	if m.ContentType() == contract.HttpMimeMultipartForm {
+		return m.request.ParseMultipartForm(32 << 20)
+	}
+
+	return m.request.ParseForm()
+}
+
+func newMockHttpProtocol(method, target, contentType, body string, params map[string]string) *mockHttpProtocol {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if contentType != `` {
+		req.Header.Set(`Content-Type`, contentType)
+	}
+	req.Header.Set(`X-Token`, `secret`)
+	req.Header.Set(`User-Agent`, `firmeve`)
+
+	return &mockHttpProtocol{request: req, params: params}
+}
+
+func TestQuery_Protocol(t *testing.T) {
+	user := new(mockUser)
+	assert.Nil(t, Query.Protocol(newMockHttpProtocol(http.MethodGet, `/users?id=1&name=simon&page=2`, ``, ``, nil), user))
+	assert.Equal(t, &mockUser{ID: 1, Name: `simon`, Page: 2}, user)
+	assert.NotNil(t, Query.Protocol(newMockHttpProtocol(http.MethodGet, `/users?page=a`, ``, ``, nil), user))
+}
+
+func TestHeader_Protocol(t *testing.T) {
+	user := new(mockUser)
+	assert.Nil(t, Header.Protocol(newMockHttpProtocol(http.MethodGet, `/`, ``, ``, nil), user))
+	assert.Equal(t, `secret`, user.Token)
+	assert.Equal(t, `firmeve`, user.Agent)
+
+	// the tags of any case match the canonical header keys
+	v := new(struct {
+		RequestID string `header:"x-Request-ID"`
+		Ignored   string `header:"-"`
+	})
+	protocol := newMockHttpProtocol(http.MethodGet, `/`, ``, ``, nil)
+	protocol.request.Header.Set(`X-Request-Id`, `1`)
+	assert.Nil(t, Header.Protocol(protocol, v))
+	assert.Equal(t, `1`, v.RequestID)
+	assert.Equal(t, ``, v.Ignored)
+}
+
+func TestURI_Protocol(t *testing.T) {
+	user := new(mockUser)
+	assert.Nil(t, URI.Protocol(newMockHttpProtocol(http.MethodGet, `/`, ``, ``, map[string]string{`id`: `3`}), user))
+	assert.Equal(t, 3, user.ID)
+	assert.NotNil(t, URI.Protocol(newMockHttpProtocol(http.MethodGet, `/`, ``, ``, map[string]string{`id`: `a`}), user))
+}
+
+func TestBind(t *testing.T) {
+	// path > body > query
+	user := new(mockUser)
+	protocol := newMockHttpProtocol(http.MethodPost, `/users/3?id=1&name=query&page=2`, contract.HttpMimeJson, `{"id":2,"name":"body"}`, map[string]string{`id`: `3`})
+	assert.Nil(t, Bind(protocol, user))
+	assert.Equal(t, &mockUser{ID: 3, Name: `body`, Page: 2}, user)
+
+	user = new(mockUser)
+	protocol = newMockHttpProtocol(http.MethodPost, `/users?name=query&page=2`, contract.HttpMimeForm, `name=body`, nil)
+	assert.Nil(t, Bind(protocol, user))
+	assert.Equal(t, &mockUser{Name: `body`, Page: 2}, user)
+
+	user = new(mockUser)
+	assert.Nil(t, Bind(newMockHttpProtocol(http.MethodGet, `/users?page=2`, ``, ``, nil), user))
+	assert.Equal(t, 2, user.Page)
+
+	assert.NotNil(t, Bind(newMockHttpProtocol(http.MethodPost, `/users`, `text/csv`, `id`, nil), user))
+}
+
+func TestMultipartForm_Protocol(t *testing.T) {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	writer.WriteField(`name`, `simon`)
+	for _, v := range []string{`avatar.png`, `a.txt`, `b.txt`} {
+		key := `attachments`
+		if v == `avatar.png` {
+			key = `avatar`
+		}
+		part, _ := writer.CreateFormFile(key, v)
+		part.Write([]byte(v))
+	}
+	writer.Close()
+
+	upload := new(struct {
+		Name        string                  `form:"name"`
+		Avatar      *multipart.FileHeader   `form:"avatar"`
+		Attachments []*multipart.FileHeader `form:"attachments"`
+		Missing     *multipart.FileHeader   `form:"missing"`
+	})
+	assert.Nil(t, MultipartForm.Protocol(newMockHttpProtocol(http.MethodPost, `/`, writer.FormDataContentType(), body.String(), nil), upload))
+	assert.Equal(t, `simon`, upload.Name)
+	assert.Equal(t, `avatar.png`, upload.Avatar.Filename)
+	assert.Len(t, upload.Attachments, 2)
+	assert.Equal(t, `b.txt`, upload.Attachments[1].Filename)
+	assert.Nil(t, upload.Missing)
+}
+
+func TestBind_Encodings(t *testing.T) {
+	message, _ := proto.Marshal(&wrappers.StringValue{Value: `simon`})
+	value := new(wrappers.StringValue)
+	assert.Nil(t, Bind(newMockHttpProtocol(http.MethodPost, `/`, contract.HttpMimeProtobuf, string(message), nil), value))
+	assert.Equal(t, `simon`, value.Value)
+	assert.NotNil(t, Protobuf.Data(message, new(mockUser)))
+
+	packed, _ := msgpack2.Marshal(map[string]interface{}{`id`: 2, `name`: `msgpack`})
+	for contentType, body := range map[string]string{
+		contract.HttpMimeXml:      `<user><id>2</id><name>xml</name></user>`,
+		contract.HttpMimeXml2:     `<user><id>2</id><name>xml</name></user>`,
+		contract.HttpMimeYaml:     "id: 2\nname: yaml",
+		contract.HttpMimeYaml2:    "id: 2\nname: yaml",
+		contract.HttpMimeMsgPack:  string(packed),
+		contract.HttpMimeMsgPack2: string(packed),
+	} {
+		user := new(mockEncodedUser)
+		assert.Nil(t, Bind(newMockHttpProtocol(http.MethodPost, `/`, contentType, body, nil), user))
+		assert.Equal(t, 2, user.ID)
+		assert.NotEmpty(t, user.Name)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register(`application/vnd.firmeve+json`, JSON)
+	defer delete(httpBindingType, `application/vnd.firmeve+json`)
+
+	user := new(mockUser)
+	assert.Nil(t, Bind(newMockHttpProtocol(http.MethodPost, `/`, `application/vnd.firmeve+json`, `{"id":1}`, nil), user))
+	assert.Equal(t, 1, user.ID)
+}
+
+func (m *mockHttpProtocol) Read(p []byte) (int, error) {
+	return m.request.Body.Read(p)
+}
+
+func TestEach(t *testing.T) {
+	users := make([]mockUser, 0)
+	user := new(mockUser)
+	protocol := newMockHttpProtocol(http.MethodPost, `/`, contract.HttpMimeJson, `[{"id":1,"name":"simon"},{"id":2}]`, nil)
+	assert.Nil(t, Each(protocol, user, func() error {
+		users = append(users, *user)
+		return nil
+	}))
+	assert.Equal(t, []mockUser{{ID: 1, Name: `simon`}, {ID: 2}}, users)
+
+	stop := errors.New(`stop`)
+	count := 0
+	protocol = newMockHttpProtocol(http.MethodPost, `/`, contract.HttpMimeJson, `[{"id":1},{"id":2}]`, nil)
+	assert.Equal(t, stop, Each(protocol, user, func() error {
+		count++
+		return stop
+	}))
+	assert.Equal(t, 1, count)
+
+	noop := func() error { return nil }
+	assert.NotNil(t, Each(newMockHttpProtocol(http.MethodPost, `/`, contract.HttpMimeJson, `{"id":1}`, nil), user, noop))
+	assert.NotNil(t, Each(newMockHttpProtocol(http.MethodPost, `/`, contract.HttpMimeJson, `[{"id":"a"}]`, nil), user, noop))
+	assert.NotNil(t, Each(newMockHttpProtocol(http.MethodPost, `/`, contract.HttpMimeJson, `[{"id":1}`, nil), user, noop))
+	assert.NotNil(t, Each(protocol, mockUser{}, noop))
+}
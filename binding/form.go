@@ -20,6 +20,9 @@ var (
 
 func (f form) Protocol(protocol contract.Protocol, v interface{}) error {
 	if p, ok := protocol.(contract.HttpProtocol); ok {
+		if err := p.ParseForm(); err != nil {
+			return err
+		}
 		return formDecoder.Decode(v, p.Values())
 	}
 
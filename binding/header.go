@@ -0,0 +1,54 @@
+package binding
+
+import (
+	"github.com/firmeve/firmeve/kernel/contract"
+	form2 "github.com/go-playground/form/v4"
+	"net/textproto"
+	"reflect"
+	"strings"
+)
+
+type (
+	header struct {
+	}
+)
+
+var (
+	headerDecoder = newHeaderDecoder()
+	Header        = header{}
+)
+
+// Bind the request headers with the `header` tag, e.g. `header:"X-Token"`, tags are case insensitive
+func (header) Protocol(protocol contract.Protocol, v interface{}) error {
+	p, ok := protocol.(contract.HttpProtocol)
+	if !ok {
+		return ProtocolTypeError
+	}
+
+	return headerDecoder.Decode(v, map[string][]string(p.Request().Header))
+}
+
+// The tags are canonicalized like the keys of http.Header, so that `x-request-id` matches X-Request-Id
+func newHeaderDecoder() *form2.Decoder {
+	decoder := newTagDecoder(`header`)
+	decoder.RegisterTagNameFunc(func(field reflect.StructField) string {
+		tag := field.Tag.Get(`header`)
+		if tag == `-` {
+			return tag
+		}
+
+		parts := strings.SplitN(tag, `,`, 2)
+		if parts[0] != `` {
+			parts[0] = textproto.CanonicalMIMEHeaderKey(parts[0])
+		}
+		return strings.Join(parts, `,`)
+	})
+
+	return decoder
+}
+
+func newTagDecoder(tag string) *form2.Decoder {
+	decoder := form2.NewDecoder()
+	decoder.SetTagName(tag)
+	return decoder
+}
@@ -15,7 +15,11 @@ var (
 )
 
 func (json) Protocol(protocol contract.Protocol, v interface{}) error {
-	message, _ := protocol.Message()
+	message, err := protocol.Message()
+	if err != nil {
+		return err
+	}
+
 	return json2.Unmarshal(message, v)
 }
 
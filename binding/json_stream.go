@@ -0,0 +1,40 @@
+package binding
+
+import (
+	json2 "encoding/json"
+	"fmt"
+	"github.com/firmeve/firmeve/kernel/contract"
+	"reflect"
+)
+
+// Decode the items of the JSON array message one by one into v and call fn after every item,
+// so that large arrays are not buffered. The message is read from the stream and can not be bound again
+func Each(protocol contract.Protocol, v interface{}, fn func() error) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return fmt.Errorf("the value %T is not a pointer", v)
+	}
+
+	decoder := json2.NewDecoder(protocol)
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	} else if delim, ok := token.(json2.Delim); !ok || delim != '[' {
+		return fmt.Errorf("the message is not a JSON array")
+	}
+
+	// The fields missing from an item must not keep the values of the previous item
+	zero := reflect.Zero(value.Elem().Type())
+	for decoder.More() {
+		value.Elem().Set(zero)
+		if err := decoder.Decode(v); err != nil {
+			return err
+		}
+		if err := fn(); err != nil {
+			return err
+		}
+	}
+
+	_, err = decoder.Token()
+	return err
+}
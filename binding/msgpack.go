@@ -0,0 +1,28 @@
+package binding
+
+import (
+	"github.com/firmeve/firmeve/kernel/contract"
+	msgpack2 "github.com/vmihailenco/msgpack/v4"
+)
+
+type (
+	msgPack struct {
+	}
+)
+
+var (
+	MsgPack = msgPack{}
+)
+
+func (msgPack) Protocol(protocol contract.Protocol, v interface{}) error {
+	message, err := protocol.Message()
+	if err != nil {
+		return err
+	}
+
+	return msgpack2.Unmarshal(message, v)
+}
+
+func (msgPack) Data(data []byte, v interface{}) error {
+	return msgpack2.Unmarshal(data, v)
+}
@@ -1,6 +1,11 @@
 package binding
 
-import "github.com/firmeve/firmeve/kernel/contract"
+import (
+	"github.com/firmeve/firmeve/kernel/contract"
+	"mime/multipart"
+	"reflect"
+	"strings"
+)
 
 type (
 	multipartForm struct {
@@ -9,14 +14,56 @@ type (
 
 var (
 	MultipartForm = multipartForm{}
+
+	fileHeaderType  = reflect.TypeOf((*multipart.FileHeader)(nil))
+	fileHeadersType = reflect.TypeOf([]*multipart.FileHeader(nil))
 )
 
+// Bind the form values, then the uploaded files to the *multipart.FileHeader and []*multipart.FileHeader fields
 func (multipartForm) Protocol(protocol contract.Protocol, v interface{}) error {
-	//@todo 暂时不解析file
-	return Form.Protocol(protocol, v)
+	if err := Form.Protocol(protocol, v); err != nil {
+		return err
+	}
+
+	p := protocol.(contract.HttpProtocol)
+	if p.Request().MultipartForm == nil {
+		return nil
+	}
+
+	bindFiles(reflect.ValueOf(v), p.Request().MultipartForm.File)
+
+	return nil
 }
 
-//@todo 这里后面会操作返回一个file对象类型
-func (multipartForm) File() {
+// Only the fields of the top level struct are bound, the key is the `form` tag or the field name
+func bindFiles(value reflect.Value, files map[string][]*multipart.FileHeader) {
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return
+	}
+
+	value = value.Elem()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if field.PkgPath != `` || (field.Type != fileHeaderType && field.Type != fileHeadersType) {
+			continue
+		}
 
+		key := strings.Split(field.Tag.Get(`form`), `,`)[0]
+		if key == `-` {
+			continue
+		} else if key == `` {
+			key = field.Name
+		}
+
+		headers := files[key]
+		if len(headers) == 0 {
+			continue
+		}
+
+		if field.Type == fileHeaderType {
+			value.Field(i).Set(reflect.ValueOf(headers[0]))
+		} else {
+			value.Field(i).Set(reflect.ValueOf(headers))
+		}
+	}
 }
@@ -0,0 +1,35 @@
+package binding
+
+import (
+	"fmt"
+	"github.com/firmeve/firmeve/kernel/contract"
+	"github.com/golang/protobuf/proto"
+)
+
+type (
+	protobuf struct {
+	}
+)
+
+var (
+	Protobuf = protobuf{}
+)
+
+// The value must be a generated proto.Message
+func (p protobuf) Protocol(protocol contract.Protocol, v interface{}) error {
+	message, err := protocol.Message()
+	if err != nil {
+		return err
+	}
+
+	return p.Data(message, v)
+}
+
+func (protobuf) Data(data []byte, v interface{}) error {
+	message, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("the value %T is not a proto.Message", v)
+	}
+
+	return proto.Unmarshal(data, message)
+}
@@ -11,6 +11,11 @@ var (
 	Query = query{}
 )
 
+// Bind the query string with the `form` tag
 func (query) Protocol(protocol contract.Protocol, v interface{}) error {
-	panic("not support")
+	if p, ok := protocol.(contract.HttpProtocol); ok {
+		return formDecoder.Decode(v, p.Request().URL.Query())
+	}
+
+	return ProtocolTypeError
 }
@@ -0,0 +1,28 @@
+package binding
+
+import "github.com/firmeve/firmeve/kernel/contract"
+
+type (
+	uri struct {
+	}
+)
+
+var (
+	uriDecoder = newTagDecoder(`uri`)
+	URI        = uri{}
+)
+
+// Bind the route parameters with the `uri` tag, e.g. `uri:"id"`
+func (uri) Protocol(protocol contract.Protocol, v interface{}) error {
+	p, ok := protocol.(contract.HttpProtocol)
+	if !ok {
+		return ProtocolTypeError
+	}
+
+	values := make(map[string][]string, len(p.Params()))
+	for key, value := range p.Params() {
+		values[key] = []string{value}
+	}
+
+	return uriDecoder.Decode(v, values)
+}
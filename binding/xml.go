@@ -0,0 +1,28 @@
+package binding
+
+import (
+	xml2 "encoding/xml"
+	"github.com/firmeve/firmeve/kernel/contract"
+)
+
+type (
+	xml struct {
+	}
+)
+
+var (
+	XML = xml{}
+)
+
+func (xml) Protocol(protocol contract.Protocol, v interface{}) error {
+	message, err := protocol.Message()
+	if err != nil {
+		return err
+	}
+
+	return xml2.Unmarshal(message, v)
+}
+
+func (xml) Data(data []byte, v interface{}) error {
+	return xml2.Unmarshal(data, v)
+}
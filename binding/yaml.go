@@ -0,0 +1,28 @@
+package binding
+
+import (
+	"github.com/firmeve/firmeve/kernel/contract"
+	yaml2 "gopkg.in/yaml.v2"
+)
+
+type (
+	yaml struct {
+	}
+)
+
+var (
+	YAML = yaml{}
+)
+
+func (yaml) Protocol(protocol contract.Protocol, v interface{}) error {
+	message, err := protocol.Message()
+	if err != nil {
+		return err
+	}
+
+	return yaml2.Unmarshal(message, v)
+}
+
+func (yaml) Data(data []byte, v interface{}) error {
+	return yaml2.Unmarshal(data, v)
+}
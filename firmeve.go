@@ -1,20 +1,26 @@
 package firmeve
 
 import (
+	"github.com/firmeve/firmeve/grpc"
 	"github.com/firmeve/firmeve/http"
 	"github.com/firmeve/firmeve/kernel"
 	"github.com/firmeve/firmeve/kernel/contract"
 	"github.com/firmeve/firmeve/support"
+	"github.com/firmeve/firmeve/tcp"
 	"github.com/spf13/cobra"
 )
 
 var (
 	defaultProviders = []contract.Provider{
 		new(http.Provider),
+		new(grpc.Provider),
+		new(tcp.Provider),
 	}
 
 	defaultCommands = []contract.Command{
 		new(http.HttpCommand),
+		new(grpc.GRPCCommand),
+		new(tcp.TCPCommand),
 	}
 )
 
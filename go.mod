@@ -6,8 +6,10 @@ require (
 	github.com/fatih/color v1.9.0
 	github.com/go-playground/form/v4 v4.1.1
 	github.com/go-redis/redis v6.15.6+incompatible
+	github.com/golang/protobuf v1.3.4
+	github.com/gorilla/websocket v1.4.2
 	github.com/guregu/null v3.4.0+incompatible
-	github.com/iris-contrib/go.uuid v2.0.0+incompatible // indirect
+	github.com/iris-contrib/go.uuid v2.0.0+incompatible
 	github.com/jinzhu/gorm v1.9.11
 	github.com/julienschmidt/httprouter v1.3.0
 	github.com/kataras/iris v11.1.1+incompatible
@@ -24,12 +26,15 @@ require (
 	github.com/spf13/viper v1.4.1-0.20191016082920-40e41dd2240a
 	github.com/stretchr/testify v1.4.0
 	github.com/ulule/paging v0.3.0
+	github.com/vmihailenco/msgpack/v4 v4.3.11
 	go.uber.org/multierr v1.4.0 // indirect
 	go.uber.org/zap v1.12.0
 	golang.org/x/crypto v0.0.0-20191227163750-53104e6ec876 // indirect
-	golang.org/x/net v0.0.0-20191209160850-c0dbc17a3553
+	golang.org/x/net v0.0.0-20200301022130-244492dfa37a
 	golang.org/x/sys v0.0.0-20200302150141-5c8b2ff67527 // indirect
 	golang.org/x/text v0.3.2 // indirect
 	golang.org/x/tools v0.0.0-20191107235519-f7ea15e60b12 // indirect
+	google.golang.org/grpc v1.27.1
 	gopkg.in/natefinch/lumberjack.v2 v2.0.0
+	gopkg.in/yaml.v2 v2.2.4
 )
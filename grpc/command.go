@@ -0,0 +1,108 @@
+package grpc
+
+import (
+	"fmt"
+	kernel2 "github.com/firmeve/firmeve/bootstrap"
+	"github.com/firmeve/firmeve/config"
+	"github.com/firmeve/firmeve/http"
+	"github.com/firmeve/firmeve/kernel"
+	"github.com/firmeve/firmeve/kernel/contract"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+type GRPCCommand struct {
+	kernel.Command
+	command *cobra.Command
+}
+
+func (c *GRPCCommand) Cmd() *cobra.Command {
+	if c.command == nil {
+		c.command = c.newCmd()
+	}
+
+	return c.command
+}
+
+func (c *GRPCCommand) newCmd() *cobra.Command {
+	c.command = new(cobra.Command)
+	c.command.Use = "grpc:serve"
+	c.command.Short = "gRPC server"
+	c.command.Flags().StringP("host", "H", ":9090", "gRPC serve address, tcp://host:port or unix:///path/to/socket (default server config grpc.host)")
+	c.command.Flags().StringP("cert-file", "", "", "TLS cert file path")
+	c.command.Flags().StringP("key-file", "", "", "TLS key file path")
+	c.command.Flags().DurationP("shutdown-timeout", "", 15*time.Second, "Graceful shutdown timeout")
+	c.command.Run = c.run
+	return c.command
+}
+
+func (c *GRPCCommand) run(cmd *cobra.Command, args []string) {
+	// bootstrap
+	kernel2.BootFromCommand(c)
+
+	logger := c.Firmeve.Get(`logger`).(contract.Loggable)
+	serverConfig := NewServerConfig(c.Firmeve.Get(`config`).(*config.Config).Item(`server`)).MergeFlags(cmd)
+
+	options, err := serverConfig.ServerOptions()
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("grpc server: %s\n", err))
+	}
+	srv := c.Firmeve.Get(`grpc.server`).(*Server).Server(options...)
+
+	event := c.Firmeve.Get(`event`).(contract.Event)
+	event.Dispatch(`grpc.starting`, map[string]interface{}{
+		`server`: srv,
+		`config`: serverConfig,
+	})
+
+	listener, err := http.Listen(serverConfig.Host, 0660)
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("listen: %s\n", err))
+	}
+
+	go func() {
+		logger.Info(fmt.Sprintf("Listening on %s://%s", listener.Addr().Network(), listener.Addr().String()))
+		if err := srv.Serve(listener); err != nil && err != grpc.ErrServerStopped {
+			logger.Fatal(fmt.Sprintf("serve: %s\n", err))
+		}
+	}()
+
+	event.Dispatch(`grpc.started`, map[string]interface{}{
+		`server`:   srv,
+		`config`:   serverConfig,
+		`listener`: listener,
+	})
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	signal.Stop(quit)
+
+	logger.Info("Shutdown gRPC Server ...")
+	event.Dispatch(`grpc.stopping`, map[string]interface{}{
+		`server`: srv,
+		`config`: serverConfig,
+	})
+	Shutdown(srv, serverConfig.ShutdownTimeout)
+
+	logger.Info("gRPC Server exiting")
+}
+
+// Stop the server gracefully, the remaining calls are canceled after the timeout
+func Shutdown(srv *grpc.Server, timeout time.Duration) {
+	stopped := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(timeout):
+		srv.Stop()
+	}
+}
@@ -0,0 +1,118 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/firmeve/firmeve/kernel/contract"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type (
+	// The protocol of a grpc call, Bind reads the request message and Render sets the response or the error status
+	GRPC struct {
+		ctx      context.Context
+		method   string
+		payload  interface{}
+		message  []byte
+		reader   *bytes.Reader
+		response interface{}
+		err      error
+	}
+)
+
+func NewGRPC(ctx context.Context, method string, payload interface{}) contract.Protocol {
+	return &GRPC{
+		ctx:     ctx,
+		method:  method,
+		payload: payload,
+	}
+}
+
+func (*GRPC) Name() string {
+	return `grpc`
+}
+
+func (g *GRPC) Read(p []byte) (int, error) {
+	if g.reader == nil {
+		message, err := g.Message()
+		if err != nil {
+			return 0, err
+		}
+		g.reader = bytes.NewReader(message)
+	}
+
+	return g.reader.Read(p)
+}
+
+// The incoming metadata of the call
+func (g *GRPC) Metadata() map[string][]string {
+	md, _ := metadata.FromIncomingContext(g.ctx)
+	return md
+}
+
+// The JSON encoded request message, empty for streams
+func (g *GRPC) Message() ([]byte, error) {
+	if g.message != nil || g.payload == nil {
+		return g.message, nil
+	}
+
+	var err error
+	g.message, err = json.Marshal(g.payload)
+
+	return g.message, err
+}
+
+func (g *GRPC) Values() map[string][]string {
+	return g.Metadata()
+}
+
+func (g *GRPC) Write(p []byte) (int, error) {
+	return 0, fmt.Errorf("the grpc response must be rendered")
+}
+
+// The full method name, e.g. /helloworld.Greeter/SayHello
+func (g *GRPC) Method() string {
+	return g.method
+}
+
+// The request message of unary calls
+func (g *GRPC) Payload() interface{} {
+	return g.payload
+}
+
+func (g *GRPC) Context() context.Context {
+	return g.ctx
+}
+
+// A status of 400 or above responds with the mapped grpc status, others respond with the value
+func (g *GRPC) Render(protocol contract.Protocol, code int, v interface{}) error {
+	if code < 400 {
+		g.response, g.err = v, nil
+		return nil
+	}
+
+	message := fmt.Sprint(v)
+	switch value := v.(type) {
+	case error:
+		message = value.Error()
+	case map[string]interface{}:
+		if m, ok := value[`message`].(string); ok {
+			message = m
+		}
+	}
+	g.response, g.err = nil, status.Error(Code(code), message)
+
+	return nil
+}
+
+// The response and the error as a grpc status
+func (g *GRPC) Response() (interface{}, error) {
+	return g.response, Status(g.err)
+}
+
+func (g *GRPC) setResponse(response interface{}, err error) {
+	g.response, g.err = response, err
+}
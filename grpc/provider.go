@@ -0,0 +1,22 @@
+package grpc
+
+import (
+	"github.com/firmeve/firmeve/container"
+	"github.com/firmeve/firmeve/kernel"
+)
+
+type Provider struct {
+	kernel.BaseProvider
+}
+
+func (p *Provider) Name() string {
+	return `grpc`
+}
+
+func (p *Provider) Register() {
+	p.Firmeve.Bind(`grpc.server`, New(p.Firmeve), container.WithShare(true))
+}
+
+func (p *Provider) Boot() {
+
+}
@@ -0,0 +1,143 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"github.com/firmeve/firmeve/kernel"
+	"github.com/firmeve/firmeve/kernel/contract"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+type (
+	// The grpc server of the container, providers register services and middleware at boot
+	Server struct {
+		Firmeve    contract.Application
+		server     *grpc.Server
+		handlers   []contract.ContextHandler
+		registrars []func(server *grpc.Server)
+	}
+
+	// The server stream of which the context carries the Firmeve context
+	serverStream struct {
+		grpc.ServerStream
+		ctx context.Context
+	}
+
+	contextKey struct{}
+)
+
+func New(firmeve contract.Application) *Server {
+	return &Server{
+		Firmeve:    firmeve,
+		handlers:   make([]contract.ContextHandler, 0),
+		registrars: make([]func(server *grpc.Server), 0),
+	}
+}
+
+// Middleware running around every unary and stream call
+func (s *Server) Use(handlers ...contract.ContextHandler) *Server {
+	s.handlers = append(s.handlers, handlers...)
+	return s
+}
+
+// Register services with the generated functions, e.g. func(server *grpc.Server) { pb.RegisterGreeterServer(server, greeter) }
+func (s *Server) Register(registrar func(server *grpc.Server)) *Server {
+	s.registrars = append(s.registrars, registrar)
+	return s
+}
+
+func (s *Server) RegisterService(desc *grpc.ServiceDesc, service interface{}) *Server {
+	return s.Register(func(server *grpc.Server) {
+		server.RegisterService(desc, service)
+	})
+}
+
+// Create the grpc server with the interceptors and the registered services, the server is created once
+func (s *Server) Server(options ...grpc.ServerOption) *grpc.Server {
+	if s.server != nil {
+		return s.server
+	}
+
+	s.server = grpc.NewServer(append([]grpc.ServerOption{
+		grpc.UnaryInterceptor(s.UnaryInterceptor),
+		grpc.StreamInterceptor(s.StreamInterceptor),
+	}, options...)...)
+	for _, registrar := range s.registrars {
+		registrar(s.server)
+	}
+
+	return s.server
+}
+
+func (s *Server) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	protocol := NewGRPC(ctx, info.FullMethod, req).(*GRPC)
+	s.serve(protocol, func(c contract.Context) {
+		protocol.setResponse(handler(context.WithValue(ctx, contextKey{}, c), req))
+		c.Next()
+	})
+
+	response, err := protocol.Response()
+	if response == nil && err == nil {
+		return nil, status.Error(codes.Internal, `the call was aborted without response`)
+	}
+
+	return response, err
+}
+
+func (s *Server) StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	protocol := NewGRPC(ss.Context(), info.FullMethod, nil).(*GRPC)
+	s.serve(protocol, func(c contract.Context) {
+		protocol.setResponse(nil, handler(srv, &serverStream{
+			ServerStream: ss,
+			ctx:          context.WithValue(ss.Context(), contextKey{}, c),
+		}))
+		c.Next()
+	})
+
+	_, err := protocol.Response()
+	return err
+}
+
+// Run the middleware and the call in a Firmeve context, panics respond Internal
+func (s *Server) serve(protocol *GRPC, call contract.ContextHandler) {
+	start := time.Now()
+	defer func() {
+		if err := recover(); err != nil {
+			protocol.setResponse(nil, status.Error(codes.Internal, fmt.Sprint(err)))
+			s.logger().Error(fmt.Sprintf("grpc %s panic: %v", protocol.Method(), err))
+		}
+
+		_, err := protocol.Response()
+		code := status.Code(err)
+		message := fmt.Sprintf("grpc %s %s %s", protocol.Method(), code, time.Since(start))
+		if code == codes.OK {
+			s.logger().Info(message)
+		} else {
+			s.logger().Error(fmt.Sprintf("%s: %s", message, err))
+		}
+	}()
+
+	handlers := make([]contract.ContextHandler, 0, len(s.handlers)+1)
+	handlers = append(handlers, s.handlers...)
+	kernel.NewContext(s.Firmeve, protocol, append(handlers, call)...).Next()
+}
+
+func (s *Server) logger() contract.Loggable {
+	return s.Firmeve.Get(`logger`).(contract.Loggable)
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// The Firmeve context of the call, nil outside of the calls of the server
+func FromContext(ctx context.Context) contract.Context {
+	if c, ok := ctx.Value(contextKey{}).(contract.Context); ok {
+		return c
+	}
+
+	return nil
+}
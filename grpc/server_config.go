@@ -0,0 +1,98 @@
+package grpc
+
+import (
+	"github.com/firmeve/firmeve/kernel/contract"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
+	"time"
+)
+
+type (
+	ServerConfig struct {
+		Host                 string
+		MaxRecvMsgSize       int
+		MaxSendMsgSize       int
+		MaxConcurrentStreams uint32
+		ConnectionTimeout    time.Duration
+		KeepaliveTime        time.Duration
+		KeepaliveTimeout     time.Duration
+		CertFile             string
+		KeyFile              string
+		ShutdownTimeout      time.Duration
+	}
+)
+
+// Create a server config from the `grpc` node of the server config item
+func NewServerConfig(config contract.Configuration) *ServerConfig {
+	config.SetDefault(`grpc.host`, `:9090`)
+	config.SetDefault(`grpc.max_recv_msg_size`, 4<<20)
+	config.SetDefault(`grpc.max_send_msg_size`, 4<<20)
+	config.SetDefault(`grpc.connection_timeout`, 120*time.Second)
+	config.SetDefault(`grpc.keepalive.time`, 2*time.Hour)
+	config.SetDefault(`grpc.keepalive.timeout`, 20*time.Second)
+	config.SetDefault(`grpc.shutdown_timeout`, 15*time.Second)
+
+	return &ServerConfig{
+		Host:                 config.GetString(`grpc.host`),
+		MaxRecvMsgSize:       config.GetInt(`grpc.max_recv_msg_size`),
+		MaxSendMsgSize:       config.GetInt(`grpc.max_send_msg_size`),
+		MaxConcurrentStreams: uint32(config.GetInt(`grpc.max_concurrent_streams`)),
+		ConnectionTimeout:    config.GetDuration(`grpc.connection_timeout`),
+		KeepaliveTime:        config.GetDuration(`grpc.keepalive.time`),
+		KeepaliveTimeout:     config.GetDuration(`grpc.keepalive.timeout`),
+		CertFile:             config.GetString(`grpc.tls.cert_file`),
+		KeyFile:              config.GetString(`grpc.tls.key_file`),
+		ShutdownTimeout:      config.GetDuration(`grpc.shutdown_timeout`),
+	}
+}
+
+// Override the config with the flags explicitly passed on the command line
+func (s *ServerConfig) MergeFlags(cmd *cobra.Command) *ServerConfig {
+	flags := cmd.Flags()
+	if flags.Changed(`host`) {
+		s.Host, _ = flags.GetString(`host`)
+	}
+	if flags.Changed(`cert-file`) {
+		s.CertFile, _ = flags.GetString(`cert-file`)
+	}
+	if flags.Changed(`key-file`) {
+		s.KeyFile, _ = flags.GetString(`key-file`)
+	}
+	if flags.Changed(`shutdown-timeout`) {
+		s.ShutdownTimeout, _ = flags.GetDuration(`shutdown-timeout`)
+	}
+
+	return s
+}
+
+func (s *ServerConfig) IsTLS() bool {
+	return s.CertFile != `` && s.KeyFile != ``
+}
+
+// The grpc server options of the config
+func (s *ServerConfig) ServerOptions() ([]grpc.ServerOption, error) {
+	options := []grpc.ServerOption{
+		grpc.MaxRecvMsgSize(s.MaxRecvMsgSize),
+		grpc.MaxSendMsgSize(s.MaxSendMsgSize),
+		grpc.ConnectionTimeout(s.ConnectionTimeout),
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:    s.KeepaliveTime,
+			Timeout: s.KeepaliveTimeout,
+		}),
+	}
+	if s.MaxConcurrentStreams > 0 {
+		options = append(options, grpc.MaxConcurrentStreams(s.MaxConcurrentStreams))
+	}
+
+	if s.IsTLS() {
+		creds, err := credentials.NewServerTLSFromFile(s.CertFile, s.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, grpc.Creds(creds))
+	}
+
+	return options, nil
+}
@@ -0,0 +1,28 @@
+package grpc
+
+import (
+	"github.com/firmeve/firmeve/config"
+	testing2 "github.com/firmeve/firmeve/testing"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestNewServerConfig(t *testing.T) {
+	app := testing2.TestingModeFirmeve()
+	serverConfig := NewServerConfig(app.Get(`config`).(*config.Config).Item(`server`))
+
+	assert.Equal(t, `0.0.0.0:29090`, serverConfig.Host)
+	assert.Equal(t, 4194304, serverConfig.MaxRecvMsgSize)
+	assert.Equal(t, 2*time.Hour, serverConfig.KeepaliveTime)
+	assert.Equal(t, 15*time.Second, serverConfig.ShutdownTimeout)
+	assert.False(t, serverConfig.IsTLS())
+
+	options, err := serverConfig.ServerOptions()
+	assert.Nil(t, err)
+	assert.Len(t, options, 4)
+
+	serverConfig.CertFile, serverConfig.KeyFile = `missing.crt`, `missing.key`
+	_, err = serverConfig.ServerOptions()
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,157 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"github.com/firmeve/firmeve/config"
+	"github.com/firmeve/firmeve/kernel"
+	"github.com/firmeve/firmeve/kernel/contract"
+	logging "github.com/firmeve/firmeve/logger"
+	testing2 "github.com/firmeve/firmeve/testing"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"net/http"
+	"testing"
+)
+
+type mockHealthServer struct {
+}
+
+func (m *mockHealthServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	switch req.Service {
+	case `missing`:
+		err := kernel.Errorf("service %s not found", req.Service)
+		err.SetMeta(`status`, http.StatusNotFound)
+		return nil, err
+	case `panic`:
+		panic(`health check panic`)
+	}
+
+	c := FromContext(ctx)
+	if c == nil || c.Entity(`user`) == nil {
+		return nil, errors.New(`the firmeve context is missing`)
+	}
+
+	return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+}
+
+func (m *mockHealthServer) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	if FromContext(stream.Context()) == nil {
+		return errors.New(`the firmeve context is missing`)
+	}
+
+	return stream.Send(&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING})
+}
+
+func newTestingClient(t *testing.T, server *Server) (healthpb.HealthClient, func()) {
+	listener := bufconn.Listen(1 << 20)
+	srv := server.Server()
+	go srv.Serve(listener)
+
+	conn, err := grpc.Dial(`bufnet`, grpc.WithInsecure(), grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) {
+		return listener.Dial()
+	}))
+	assert.Nil(t, err)
+
+	return healthpb.NewHealthClient(conn), func() {
+		conn.Close()
+		srv.Stop()
+	}
+}
+
+func newTestingServer() *Server {
+	app := testing2.TestingModeFirmeve()
+	app.Bind(`logger`, logging.New(app.Get(`config`).(*config.Config).Item(`logging`)))
+
+	return New(app)
+}
+
+func TestServer_Unary(t *testing.T) {
+	methods := make([]string, 0)
+	server := newTestingServer().Use(func(c contract.Context) {
+		protocol := c.Protocol().(*GRPC)
+		methods = append(methods, protocol.Method())
+
+		request := new(healthpb.HealthCheckRequest)
+		assert.Nil(t, c.Bind(request))
+		if request.Service == `forbidden` || len(c.Protocol().Metadata()[`user`]) == 0 {
+			c.Error(http.StatusForbidden, kernel.Error(`forbidden`))
+			c.Abort()
+			return
+		}
+
+		c.AddEntity(`user`, c.Protocol().Metadata()[`user`][0])
+		c.Next()
+	}).Register(func(server *grpc.Server) {
+		healthpb.RegisterHealthServer(server, new(mockHealthServer))
+	})
+
+	client, closeClient := newTestingClient(t, server)
+	defer closeClient()
+	ctx := metadata.AppendToOutgoingContext(context.Background(), `user`, `simon`)
+
+	response, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+	assert.Nil(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, response.Status)
+	assert.Equal(t, []string{`/grpc.health.v1.Health/Check`}, methods)
+
+	_, err = client.Check(ctx, &healthpb.HealthCheckRequest{Service: `forbidden`})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Equal(t, `forbidden`, status.Convert(err).Message())
+
+	_, err = client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = client.Check(ctx, &healthpb.HealthCheckRequest{Service: `missing`})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, `service missing not found`, status.Convert(err).Message())
+
+	_, err = client.Check(ctx, &healthpb.HealthCheckRequest{Service: `panic`})
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
+
+func TestServer_Stream(t *testing.T) {
+	names := make([]string, 0)
+	server := newTestingServer().Use(func(c contract.Context) {
+		names = append(names, c.Protocol().Name())
+		c.Next()
+	}).Register(func(server *grpc.Server) {
+		healthpb.RegisterHealthServer(server, new(mockHealthServer))
+	})
+
+	client, closeClient := newTestingClient(t, server)
+	defer closeClient()
+
+	stream, err := client.Watch(context.Background(), &healthpb.HealthCheckRequest{})
+	assert.Nil(t, err)
+	response, err := stream.Recv()
+	assert.Nil(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, response.Status)
+	assert.Equal(t, []string{`grpc`}, names)
+}
+
+func TestStatus(t *testing.T) {
+	assert.Nil(t, Status(nil))
+	assert.Equal(t, codes.Canceled, status.Code(Status(context.Canceled)))
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(Status(context.DeadlineExceeded)))
+	assert.Equal(t, codes.Unknown, status.Code(Status(errors.New(`error`))))
+	assert.Equal(t, codes.AlreadyExists, status.Code(Status(status.Error(codes.AlreadyExists, `exists`))))
+
+	err := kernel.Error(`unauthenticated`)
+	err.SetMeta(`status`, http.StatusUnauthorized)
+	assert.Equal(t, codes.Unauthenticated, status.Code(Status(err)))
+	assert.Equal(t, codes.Unauthenticated, status.Code(Status(kernel.ErrorWarp(err))))
+}
+
+func TestCode(t *testing.T) {
+	assert.Equal(t, codes.NotFound, Code(http.StatusNotFound))
+	assert.Equal(t, codes.FailedPrecondition, Code(http.StatusTeapot))
+	assert.Equal(t, codes.Internal, Code(http.StatusBadGateway))
+	assert.Equal(t, codes.Unknown, Code(http.StatusOK))
+}
@@ -0,0 +1,65 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"github.com/firmeve/firmeve/kernel/contract"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http"
+)
+
+var (
+	httpCodes = map[int]codes.Code{
+		http.StatusBadRequest:          codes.InvalidArgument,
+		http.StatusUnauthorized:        codes.Unauthenticated,
+		http.StatusForbidden:           codes.PermissionDenied,
+		http.StatusNotFound:            codes.NotFound,
+		http.StatusConflict:            codes.AlreadyExists,
+		http.StatusPreconditionFailed:  codes.FailedPrecondition,
+		http.StatusUnprocessableEntity: codes.InvalidArgument,
+		http.StatusTooManyRequests:     codes.ResourceExhausted,
+		http.StatusRequestTimeout:      codes.DeadlineExceeded,
+		http.StatusInternalServerError: codes.Internal,
+		http.StatusNotImplemented:      codes.Unimplemented,
+		http.StatusServiceUnavailable:  codes.Unavailable,
+		http.StatusGatewayTimeout:      codes.DeadlineExceeded,
+	}
+)
+
+// Map the http status to the grpc code
+func Code(httpStatus int) codes.Code {
+	if code, ok := httpCodes[httpStatus]; ok {
+		return code
+	} else if httpStatus >= 400 && httpStatus < 500 {
+		return codes.FailedPrecondition
+	} else if httpStatus >= 500 {
+		return codes.Internal
+	}
+
+	return codes.Unknown
+}
+
+// Convert the error into a grpc status error. Firmeve errors carry the http status in the `status` meta,
+// e.g. kernel.Errorf("user %d not found", id) with SetMeta(`status`, 404) responds NotFound
+func Status(err error) error {
+	if err == nil {
+		return nil
+	} else if _, ok := err.(interface{ GRPCStatus() *status.Status }); ok {
+		return err
+	} else if errors.Is(err, context.Canceled) {
+		return status.Error(codes.Canceled, err.Error())
+	} else if errors.Is(err, context.DeadlineExceeded) {
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if v, ok := e.(contract.Error); ok {
+			if httpStatus, ok := v.Meta()[`status`].(int); ok {
+				return status.Error(Code(httpStatus), err.Error())
+			}
+		}
+	}
+
+	return status.Error(codes.Unknown, err.Error())
+}
@@ -0,0 +1,139 @@
+package http
+
+import (
+	"bytes"
+	"github.com/firmeve/firmeve/kernel/contract"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func serveTestingBodyRequest(router *Router, path, body string, chunked bool) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set(`Content-Type`, contract.HttpMimeJson)
+	if chunked {
+		req.ContentLength = -1
+		req.Body = ioutil.NopCloser(strings.NewReader(body))
+	}
+	router.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestRouter_SetMaxBodySize(t *testing.T) {
+	router := newTestingRouter().SetMaxBodySize(16)
+	router.Use(func(c contract.Context) {
+		c.Protocol().(contract.HttpProtocol).ResponseWriter().Header().Set(`X-Global`, `1`)
+		c.Next()
+	})
+	handler := func(c contract.Context) {
+		v := make(map[string]interface{}, 0)
+		// the context has answered the body exceeding the limit
+		if err := c.Bind(&v); err != nil {
+			return
+		}
+		c.Render(http.StatusOK, v)
+		c.Next()
+	}
+	router.POST(`/`, handler)
+	router.POST(`/large`, handler).MaxBodySize(64)
+	router.POST(`/unlimited`, handler).MaxBodySize(-1)
+
+	body := `{"name":"firmeve-firmeve"}`
+	w := serveTestingBodyRequest(router, `/`, body, false)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Equal(t, 1, strings.Count(w.Body.String(), `the request body exceeds 16 bytes`))
+	// the declared length is checked within the global middleware
+	assert.Equal(t, `1`, w.Header().Get(`X-Global`))
+
+	w = serveTestingBodyRequest(router, `/`, body, true)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Equal(t, 1, strings.Count(w.Body.String(), `the request body exceeds 16 bytes`))
+
+	assert.Equal(t, http.StatusOK, serveTestingBodyRequest(router, `/`, `{"a":1}`, true).Code)
+	assert.Equal(t, http.StatusOK, serveTestingBodyRequest(router, `/large`, body, false).Code)
+	assert.Equal(t, http.StatusOK, serveTestingBodyRequest(router, `/unlimited`, strings.Repeat(` `, 100)+body, true).Code)
+}
+
+func TestRouter_SetMaxBodySize_Form(t *testing.T) {
+	router := newTestingRouter().SetMaxBodySize(16)
+	router.POST(`/bind`, func(c contract.Context) {
+		v := new(struct {
+			Name string `form:"name"`
+		})
+		if err := c.Bind(v); err != nil {
+			return
+		}
+		c.Protocol().Write([]byte(v.Name))
+		c.Next()
+	})
+	router.POST(`/input`, func(c contract.Context) {
+		if c.Input().Err() != nil {
+			return
+		}
+		c.Protocol().Write([]byte(c.Input().String(`name`)))
+		c.Next()
+	})
+
+	serve := func(path, contentType, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.Header.Set(`Content-Type`, contentType)
+		// chunked bodies without the content length are only limited when read
+		req.ContentLength = -1
+		req.Body = ioutil.NopCloser(strings.NewReader(body))
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	multipartBody := func(name string) (string, string) {
+		buf := new(bytes.Buffer)
+		writer := multipart.NewWriter(buf)
+		writer.WriteField(`name`, name)
+		writer.Close()
+		return writer.FormDataContentType(), buf.String()
+	}
+
+	for _, path := range []string{`/bind`, `/input`} {
+		w := serve(path, contract.HttpMimeForm, `name=`+strings.Repeat(`a`, 32))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.Contains(t, w.Body.String(), `the request body exceeds 16 bytes`)
+
+		w = serve(path, contract.HttpMimeForm, `name=simon`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `simon`, w.Body.String())
+
+		contentType, body := multipartBody(`simon`)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, serve(path, contentType, body).Code)
+	}
+
+	router.POST(`/multipart`, func(c contract.Context) {
+		v := new(struct {
+			Name string `form:"name"`
+		})
+		if err := c.Bind(v); err != nil {
+			return
+		}
+		c.Protocol().Write([]byte(v.Name))
+		c.Next()
+	}).MaxBodySize(1024)
+	contentType, body := multipartBody(`simon`)
+	assert.Equal(t, `simon`, serve(`/multipart`, contentType, body).Body.String())
+}
+
+func TestHttp_Read(t *testing.T) {
+	protocol := NewHttp(httptest.NewRequest(http.MethodPost, `/`, strings.NewReader(`message`)), httptest.NewRecorder()).(*Http)
+	message, err := protocol.Message()
+	assert.Nil(t, err)
+	assert.Equal(t, `message`, string(message))
+
+	// the body read by Message is read again from the message
+	content, err := ioutil.ReadAll(protocol)
+	assert.Nil(t, err)
+	assert.Equal(t, `message`, string(content))
+}
@@ -0,0 +1,98 @@
+package http
+
+import (
+	"context"
+	"github.com/julienschmidt/httprouter"
+	"net"
+	"net/http"
+	"regexp"
+	"sort"
+)
+
+type (
+	domain struct {
+		pattern string
+		regexp  *regexp.Regexp
+		names   []string
+		router  *httprouter.Router
+	}
+
+	hostParamsKey struct{}
+)
+
+var (
+	domainPlaceholder = regexp.MustCompile(`\\\{(\w+)\\\}`)
+)
+
+// A group of routes only matching the host, placeholders such as `{tenant}.example.com` are merged into the route params.
+// Requests to the host not matching any route of the domain fall through to the routes without domain
+func (r *Router) Domain(pattern string) *Group {
+	d, ok := r.domains[pattern]
+	if !ok {
+		d = newDomain(pattern, r.router)
+		r.domains[pattern] = d
+		r.domainPatterns = append(r.domainPatterns, pattern)
+		// Domains with fewer placeholders are more specific and match first
+		sort.SliceStable(r.domainPatterns, func(i, j int) bool {
+			return len(r.domains[r.domainPatterns[i]].names) < len(r.domains[r.domainPatterns[j]].names)
+		})
+	}
+
+	group := newGroup(r)
+	group.domain = d
+	return group
+}
+
+// Find the domain matching the request host and put the host params into the request context
+func (r *Router) matchDomain(req *http.Request) (*domain, *http.Request) {
+	host := req.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	for _, pattern := range r.domainPatterns {
+		d := r.domains[pattern]
+		values := d.regexp.FindStringSubmatch(host)
+		if values == nil {
+			continue
+		}
+
+		params := make(httprouter.Params, 0, len(d.names))
+		for i, name := range d.names {
+			params = append(params, httprouter.Param{Key: name, Value: values[i+1]})
+		}
+
+		return d, req.WithContext(context.WithValue(req.Context(), hostParamsKey{}, params))
+	}
+
+	return nil, req
+}
+
+func hostParams(req *http.Request) httprouter.Params {
+	if params, ok := req.Context().Value(hostParamsKey{}).(httprouter.Params); ok {
+		return params
+	}
+
+	return nil
+}
+
+func newDomain(pattern string, fallback http.Handler) *domain {
+	names := make([]string, 0)
+	// QuoteMeta escapes the braces of the placeholders
+	expr := domainPlaceholder.ReplaceAllStringFunc(regexp.QuoteMeta(pattern), func(s string) string {
+		names = append(names, s[2:len(s)-2])
+		return `([^.]+)`
+	})
+
+	router := httprouter.New()
+	// Fall through to the routes without domain instead of answering 405
+	router.HandleMethodNotAllowed = false
+	router.NotFound = fallback
+
+	return &domain{
+		pattern: pattern,
+		regexp:  regexp.MustCompile(`(?i)^` + expr + `$`),
+		names:   names,
+		router:  router,
+	}
+}
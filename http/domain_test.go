@@ -0,0 +1,49 @@
+package http
+
+import (
+	"github.com/firmeve/firmeve/kernel/contract"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func serveTestingHostRequest(router *Router, method, host, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, nil)
+	req.Host = host
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestNewDomain(t *testing.T) {
+	d := newDomain(`{tenant}.{region}.example.com`, nil)
+	assert.Equal(t, []string{`tenant`, `region`}, d.names)
+	assert.Equal(t, []string{`acme.eu.example.com`, `acme`, `eu`}, d.regexp.FindStringSubmatch(`acme.eu.example.com`))
+	assert.Nil(t, d.regexp.FindStringSubmatch(`acme.eu.example.org`))
+	assert.Nil(t, d.regexp.FindStringSubmatch(`a.b.eu.example.com`))
+}
+
+func TestRouter_Domain(t *testing.T) {
+	router := newTestingRouter()
+	paramHandler := func(ctx contract.Context) {
+		ctx.Protocol().Write([]byte(ctx.Param(`tenant`) + `:` + ctx.Param(`id`)))
+		ctx.Next()
+	}
+
+	tenant := router.Domain(`{tenant}.example.com`).Before(writeHandler(`tenant,`))
+	tenant.GET(`/users/:id`, paramHandler)
+	tenant.Group(`/v1`).GET(`/users/:id`, paramHandler).Where(`tenant`, `[a-z]+`)
+	router.Domain(`admin.example.com`).GET(`/users/:id`, writeHandler(`admin`))
+	router.GET(`/users/:id`, writeHandler(`main`))
+	router.GET(`/about`, writeHandler(`about`))
+
+	assert.Equal(t, `admin`, serveTestingHostRequest(router, http.MethodGet, `admin.example.com`, `/users/1`).Body.String())
+	assert.Equal(t, `tenant,acme:1`, serveTestingHostRequest(router, http.MethodGet, `acme.example.com:8080`, `/users/1`).Body.String())
+	assert.Equal(t, `tenant,acme:2`, serveTestingHostRequest(router, http.MethodGet, `acme.example.com`, `/v1/users/2`).Body.String())
+	assert.Equal(t, http.StatusNotFound, serveTestingHostRequest(router, http.MethodGet, `acme1.example.com`, `/v1/users/2`).Code)
+	assert.Equal(t, `main`, serveTestingHostRequest(router, http.MethodGet, `example.com`, `/users/1`).Body.String())
+	// fall through to the routes without domain
+	assert.Equal(t, `about`, serveTestingHostRequest(router, http.MethodGet, `acme.example.com`, `/about`).Body.String())
+	assertBaseRoute(t, router, http.MethodGet, `{tenant}.example.com/users/:id`, ``, 1, 0)
+}
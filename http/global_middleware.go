@@ -0,0 +1,39 @@
+package http
+
+import "github.com/firmeve/firmeve/kernel/contract"
+
+type (
+	// Middleware registered on the router which runs for every route, NotFound and static files
+	GlobalMiddleware struct {
+		router   *Router
+		handlers []contract.ContextHandler
+		except   map[string]struct{}
+	}
+)
+
+// Skip the middleware for the named routes
+func (g *GlobalMiddleware) Except(names ...string) *GlobalMiddleware {
+	g.router.mustNotFrozen()
+	for _, name := range names {
+		g.except[name] = struct{}{}
+	}
+
+	return g
+}
+
+func (g *GlobalMiddleware) excepted(name string) bool {
+	if name == `` {
+		return false
+	}
+
+	_, ok := g.except[name]
+	return ok
+}
+
+func newGlobalMiddleware(router *Router, handlers []contract.ContextHandler) *GlobalMiddleware {
+	return &GlobalMiddleware{
+		router:   router,
+		handlers: handlers,
+		except:   make(map[string]struct{}, 0),
+	}
+}
@@ -0,0 +1,55 @@
+package http
+
+import (
+	"github.com/firmeve/firmeve/kernel/contract"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeHandler(content string) contract.ContextHandler {
+	return func(ctx contract.Context) {
+		ctx.Protocol().Write([]byte(content))
+		ctx.Next()
+	}
+}
+
+func TestRouter_Use(t *testing.T) {
+	router := newTestingRouter()
+	// registered before the global middleware
+	router.GET("/before", writeHandler("handler")).Before(writeHandler("route-before,")).After(writeHandler(",route-after"))
+	router.Use(writeHandler("global-1,"))
+	router.UseAfter(writeHandler(",global-after"))
+	router.Use(writeHandler("global-2,")).Except("login")
+	// registered after the global middleware
+	router.Group("/v1").Before(writeHandler("group,")).GET("/after", writeHandler("handler"))
+	router.GET("/login", writeHandler("login")).Name("login")
+	router.NotFound(writeHandler("not found"))
+
+	assert.Equal(t, "global-1,global-2,route-before,handler,route-after,global-after", serveTestingRequest(router, http.MethodGet, "/before").Body.String())
+	assert.Equal(t, "global-1,global-2,group,handler,global-after", serveTestingRequest(router, http.MethodGet, "/v1/after").Body.String())
+	assert.Equal(t, "global-1,login,global-after", serveTestingRequest(router, http.MethodGet, "/login").Body.String())
+	assert.Equal(t, "global-1,global-2,not found,global-after", serveTestingRequest(router, http.MethodGet, "/nothing").Body.String())
+}
+
+func TestRouter_Use_Static(t *testing.T) {
+	dir, err := ioutil.TempDir(``, `firmeve`)
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, `file.txt`), []byte(`static`), 0644))
+
+	router := newTestingRouter()
+	router.Static("/assets", dir)
+	router.Use(func(ctx contract.Context) {
+		ctx.Protocol().(contract.HttpProtocol).ResponseWriter().Header().Set(`X-Global`, `1`)
+		ctx.Next()
+	})
+
+	w := serveTestingRequest(router, http.MethodGet, "/assets/file.txt")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `static`, w.Body.String())
+	assert.Equal(t, `1`, w.Header().Get(`X-Global`))
+}
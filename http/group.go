@@ -10,7 +10,9 @@ type Group struct {
 	prefix         string
 	beforeHandlers []contract.ContextHandler
 	afterHandlers  []contract.ContextHandler
+	middleware     []string
 	router         *Router
+	domain         *domain
 }
 
 func (g *Group) Prefix(prefix string) *Group {
@@ -28,38 +30,65 @@ func (g *Group) Before(handlers ...contract.ContextHandler) *Group {
 	return g
 }
 
+// Middleware aliases or groups applied to every route of the group
+func (g *Group) Middleware(names ...string) *Group {
+	g.middleware = append(g.middleware, names...)
+	return g
+}
+
 func (g *Group) GET(path string, handler contract.ContextHandler) *Route {
-	return g.createRoute(http.MethodGet, path, handler)
+	return g.createRoute([]string{http.MethodGet}, path, handler)
 }
 
 func (g *Group) POST(path string, handler contract.ContextHandler) *Route {
-	return g.createRoute(http.MethodPost, path, handler)
+	return g.createRoute([]string{http.MethodPost}, path, handler)
 }
 
 func (g *Group) PUT(path string, handler contract.ContextHandler) *Route {
-	return g.createRoute(http.MethodPut, path, handler)
+	return g.createRoute([]string{http.MethodPut}, path, handler)
 }
 
 func (g *Group) PATCH(path string, handler contract.ContextHandler) *Route {
-	return g.createRoute(http.MethodPatch, path, handler)
+	return g.createRoute([]string{http.MethodPatch}, path, handler)
 }
 
 func (g *Group) DELETE(path string, handler contract.ContextHandler) *Route {
-	return g.createRoute(http.MethodDelete, path, handler)
+	return g.createRoute([]string{http.MethodDelete}, path, handler)
 }
 
 func (g *Group) OPTIONS(path string, handler contract.ContextHandler) *Route {
-	return g.createRoute(http.MethodOptions, path, handler)
+	return g.createRoute([]string{http.MethodOptions}, path, handler)
+}
+
+func (g *Group) HEAD(path string, handler contract.ContextHandler) *Route {
+	return g.createRoute([]string{http.MethodHead}, path, handler)
+}
+
+// Register the route for the methods of applications, as Router.Any
+func (g *Group) Any(path string, handler contract.ContextHandler) *Route {
+	return g.createRoute(anyMethods, path, handler)
+}
+
+// Register the route for the given http methods
+func (g *Group) Match(methods []string, path string, handler contract.ContextHandler) *Route {
+	return g.createRoute(methods, path, handler)
 }
 
 func (g *Group) Group(prefix string) *Group {
-	return newGroup(g.router).Prefix(strings.Join([]string{g.prefix, prefix}, ``)).After(g.afterHandlers...).Before(g.beforeHandlers...)
+	group := newGroup(g.router)
+	group.domain = g.domain
+	return group.Prefix(strings.Join([]string{g.prefix, prefix}, ``)).After(g.afterHandlers...).Before(g.beforeHandlers...).Middleware(g.middleware...)
 }
 
-func (g *Group) createRoute(method string, path string, handler contract.ContextHandler) *Route {
+func (g *Group) createRoute(methods []string, path string, handler contract.ContextHandler) *Route {
 	path = strings.Join([]string{g.prefix, path}, ``)
 
-	return g.router.createRoute(method, path, handler).Before(g.beforeHandlers...).After(g.afterHandlers...)
+	return g.apply(g.router.createDomainRoute(g.domain, methods, path, handler))
+}
+
+// Apply the group handlers and middleware to the route
+func (g *Group) apply(route *Route) *Route {
+	return route.Before(g.beforeHandlers...).After(g.afterHandlers...).Middleware(g.middleware...)
 }
 
 func newGroup(router *Router) *Group {
@@ -67,5 +96,6 @@ func newGroup(router *Router) *Group {
 		router:         router,
 		beforeHandlers: make([]contract.ContextHandler, 0),
 		afterHandlers:  make([]contract.ContextHandler, 0),
+		middleware:     make([]string, 0),
 	}
 }
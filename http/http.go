@@ -1,7 +1,10 @@
 package http
 
 import (
+	"bytes"
+	"github.com/firmeve/firmeve/kernel"
 	"github.com/firmeve/firmeve/kernel/contract"
+	render2 "github.com/firmeve/firmeve/render"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -14,17 +17,26 @@ type (
 		responseWriter http.ResponseWriter
 		message        []byte
 		status         int
+		params         map[string]string
+		maxMemory      int64
+		maxBodySize    int64
+		prettyJSON     bool
+		formParsed     bool
+		formErr        error
+		reader         *bytes.Reader
 	}
 )
 
 var (
-	defaultMaxSize int64 = 32 << 20
+	// The memory of the multipart form, the remaining files are stored on disk
+	defaultMaxMemory int64 = 32 << 20
 )
 
 func NewHttp(request *http.Request, responseWriter http.ResponseWriter) contract.Protocol {
 	return &Http{
 		request:        request,
 		responseWriter: responseWriter,
+		maxMemory:      defaultMaxMemory,
 	}
 }
 
@@ -32,8 +44,17 @@ func (*Http) Name() string {
 	return `http`
 }
 
+// Read the body, or the message once the body has been read by Message
 func (h *Http) Read(p []byte) (n int, err error) {
-	return h.request.Body.Read(p)
+	if h.message != nil {
+		if h.reader == nil {
+			h.reader = bytes.NewReader(h.message)
+		}
+		return h.reader.Read(p)
+	}
+
+	n, err = h.request.Body.Read(p)
+	return n, h.bodyError(err)
 }
 
 func (h *Http) Metadata() map[string][]string {
@@ -45,26 +66,48 @@ func (h *Http) Message() ([]byte, error) {
 		return h.message, nil
 	}
 
-	var err error
-	h.message, err = ioutil.ReadAll(h.request.Body)
+	message, err := ioutil.ReadAll(h.request.Body)
+	if err != nil {
+		return nil, h.bodyError(err)
+	}
+	h.message = message
+
+	return h.message, nil
+}
+
+// Limit the size of the request body, reading beyond the limit fails with an error of the status 413
+func (h *Http) SetMaxBodySize(size int64) {
+	h.maxBodySize = size
+	h.request.Body = http.MaxBytesReader(h.responseWriter, h.request.Body, size)
+}
+
+// The error of http.MaxBytesReader carries no type before Go 1.19, the multipart reader wraps it in its message
+func (h *Http) bodyError(err error) error {
+	if err != nil && h.maxBodySize > 0 && strings.Contains(err.Error(), `http: request body too large`) {
+		return bodyTooLarge(h.maxBodySize)
+	}
+
+	return err
+}
+
+func bodyTooLarge(limit int64) error {
+	err := kernel.Errorf("the request body exceeds %d bytes", limit)
+	err.SetMeta(`status`, http.StatusRequestEntityTooLarge)
+	return err
+}
+
+func (h *Http) SetParams(params map[string]string) {
+	h.params = params
+}
 
-	return h.message, err
+func (h *Http) Params() map[string]string {
+	return h.params
 }
 
-//func (c *Context) SetParams(params Params) *Context {
-//	c.Params = params
-//	return c
-//}
-//
-//func (c *Context) SetRoute(route *Route) *Context {
-//	c.route = route
-//	return c
-//}
-//
-//func (c *Context) Param(key string) string {
-//	value, _ := c.Params[key]
-//	return value
-//}
+func (h *Http) Param(key string) string {
+	value, _ := h.params[key]
+	return value
+}
 
 func (h *Http) Request() *http.Request {
 	return h.request
@@ -90,15 +133,9 @@ func (h *Http) IsContentType(key string) bool {
 	return h.ContentType() == key
 }
 
+// Whether the Accept header accepts the type, wildcard ranges such as */* accept every type
 func (h *Http) IsAccept(key string) bool {
-	accept := h.Accept()
-	for _, v := range accept {
-		if v == key {
-			return true
-		}
-	}
-
-	return false
+	return render2.Negotiate(h.Header(`Accept`), key) != ``
 }
 
 func (h *Http) IsMethod(key string) bool {
@@ -136,8 +173,9 @@ func (h *Http) ContentType() string {
 	return strings.Split(h.Header(`Content-Type`), `;`)[0]
 }
 
+// The accepted types ordered by preference
 func (h *Http) Accept() []string {
-	return strings.Split(h.Header(`Accept`), `,`)
+	return render2.AcceptedTypes(h.Header(`Accept`))
 }
 
 func (h *Http) Values() map[string][]string {
@@ -146,12 +184,80 @@ func (h *Http) Values() map[string][]string {
 	}
 
 	switch h.ContentType() {
-	case contract.HttpMimeForm:
-		return h.request.Form
-	case contract.HttpMimeMultipartForm:
-		h.request.ParseMultipartForm(defaultMaxSize)
+	case contract.HttpMimeForm, contract.HttpMimeMultipartForm:
+		h.ParseForm()
 		return h.request.Form
 	}
 
 	return nil
 }
+
+// The error is kept, the request only reports it on the first parse
+func (h *Http) ParseForm() error {
+	if h.formParsed {
+		return h.formErr
+	}
+	h.formParsed = true
+
+	switch h.ContentType() {
+	case contract.HttpMimeForm:
+		h.formErr = h.bodyError(h.request.ParseForm())
+	case contract.HttpMimeMultipartForm:
+		h.formErr = h.bodyError(h.request.ParseMultipartForm(h.maxMemory))
+	}
+
+	return h.formErr
+}
+
+func (h *Http) UploadedFile(key string) (contract.UploadedFile, error) {
+	if err := h.parseMultipartForm(); err != nil {
+		return nil, err
+	}
+
+	files, err := h.UploadedFiles(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return files[0], nil
+}
+
+func (h *Http) UploadedFiles(key string) ([]contract.UploadedFile, error) {
+	if err := h.parseMultipartForm(); err != nil {
+		return nil, err
+	}
+
+	headers := h.request.MultipartForm.File[key]
+	if len(headers) == 0 {
+		return nil, http.ErrMissingFile
+	}
+
+	files := make([]contract.UploadedFile, 0, len(headers))
+	for _, header := range headers {
+		files = append(files, NewUploadedFile(header))
+	}
+
+	return files, nil
+}
+
+// The max memory of the multipart form
+func (h *Http) SetMaxMemory(maxMemory int64) {
+	h.maxMemory = maxMemory
+}
+
+// Indent every json response of the request, such as in development mode
+func (h *Http) SetPrettyJSON(pretty bool) {
+	h.prettyJSON = pretty
+}
+
+func (h *Http) PrettyJSON() bool {
+	return h.prettyJSON
+}
+
+func (h *Http) parseMultipartForm() error {
+	if h.IsContentType(contract.HttpMimeMultipartForm) {
+		return h.ParseForm()
+	}
+
+	return h.request.ParseMultipartForm(h.maxMemory)
+}
@@ -0,0 +1,121 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	// The first file descriptor passed by systemd socket activation
+	listenFdsStart = 3
+)
+
+// Split an address such as `unix:///tmp/firmeve.sock` or `tcp://0.0.0.0:80` into network and address,
+// an address without scheme is a tcp address
+func ParseAddress(address string) (string, string) {
+	if i := strings.Index(address, `://`); i != -1 {
+		return address[:i], address[i+3:]
+	}
+
+	return `tcp`, address
+}
+
+// Listen on a tcp or unix domain socket address, unix socket files are created with the given mode
+func Listen(address string, mode os.FileMode) (net.Listener, error) {
+	network, addr := ParseAddress(address)
+	switch network {
+	case `tcp`, `tcp4`, `tcp6`:
+		return net.Listen(network, addr)
+	case `unix`:
+		return listenUnix(addr, mode)
+	}
+
+	return nil, fmt.Errorf("unsupported network %s", network)
+}
+
+func listenUnix(file string, mode os.FileMode) (net.Listener, error) {
+	// Remove the stale socket left by a previous process, a socket still accepting connections is in use
+	if info, err := os.Stat(file); err == nil && info.Mode()&os.ModeSocket != 0 {
+		conn, err := net.DialTimeout(`unix`, file, time.Second)
+		if err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("the socket %s is in use", file)
+		}
+		if !errors.Is(err, syscall.ECONNREFUSED) {
+			return nil, err
+		}
+		if err := os.Remove(file); err != nil {
+			return nil, err
+		}
+	}
+
+	listener, err := net.Listen(`unix`, file)
+	if err != nil {
+		return nil, err
+	}
+
+	if mode != 0 {
+		if err := os.Chmod(file, mode); err != nil {
+			listener.Close()
+			return nil, err
+		}
+	}
+
+	return listener, nil
+}
+
+// Listeners passed by systemd socket activation (LISTEN_FDS), nil when the process was not activated.
+// The environment is cleared so that child processes do not inherit it.
+func InheritedListeners() ([]net.Listener, error) {
+	defer func() {
+		os.Unsetenv(`LISTEN_PID`)
+		os.Unsetenv(`LISTEN_FDS`)
+		os.Unsetenv(`LISTEN_FDNAMES`)
+	}()
+
+	if pid := os.Getenv(`LISTEN_PID`); pid != `` {
+		if v, err := strconv.Atoi(pid); err != nil || v != os.Getpid() {
+			return nil, nil
+		}
+	}
+
+	fds, err := strconv.Atoi(os.Getenv(`LISTEN_FDS`))
+	if err != nil || fds <= 0 {
+		return nil, nil
+	}
+
+	names := strings.Split(os.Getenv(`LISTEN_FDNAMES`), `:`)
+	listeners := make([]net.Listener, 0, fds)
+	for i := 0; i < fds; i++ {
+		fd := listenFdsStart + i
+		syscall.CloseOnExec(fd)
+
+		name := `LISTEN_FD_` + strconv.Itoa(fd)
+		if i < len(names) && names[i] != `` {
+			name = names[i]
+		}
+
+		file := os.NewFile(uintptr(fd), name)
+		listener, err := net.FileListener(file)
+		file.Close()
+		if err != nil {
+			closeListeners(listeners)
+			return nil, fmt.Errorf("inherit listener %s: %w", name, err)
+		}
+		listeners = append(listeners, listener)
+	}
+
+	return listeners, nil
+}
+
+func closeListeners(listeners []net.Listener) {
+	for i := range listeners {
+		listeners[i].Close()
+	}
+}
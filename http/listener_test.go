@@ -0,0 +1,92 @@
+package http
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestParseAddress(t *testing.T) {
+	network, address := ParseAddress(`unix:///tmp/firmeve.sock`)
+	assert.Equal(t, `unix`, network)
+	assert.Equal(t, `/tmp/firmeve.sock`, address)
+
+	network, address = ParseAddress(`tcp://:8081`)
+	assert.Equal(t, `tcp`, network)
+	assert.Equal(t, `:8081`, address)
+
+	network, address = ParseAddress(`127.0.0.1:80`)
+	assert.Equal(t, `tcp`, network)
+	assert.Equal(t, `127.0.0.1:80`, address)
+}
+
+func TestListen_Unix(t *testing.T) {
+	dir, err := ioutil.TempDir(``, `firmeve`)
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, `http.sock`)
+	listener, err := Listen(`unix://`+file, 0600)
+	assert.Nil(t, err)
+	assert.Equal(t, `unix`, listener.Addr().Network())
+
+	info, err := os.Stat(file)
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	listener.Close()
+
+	_, err = Listen(`udp://:0`, 0)
+	assert.NotNil(t, err)
+}
+
+func TestListen_Unix_Stale(t *testing.T) {
+	dir, err := ioutil.TempDir(``, `firmeve`)
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, `http.sock`)
+	listener, err := Listen(`unix://`+file, 0)
+	assert.Nil(t, err)
+
+	// the socket of a running server is kept
+	_, err = Listen(`unix://`+file, 0)
+	assert.EqualError(t, err, `the socket `+file+` is in use`)
+
+	// the socket left by a stopped server is replaced
+	listener.(*net.UnixListener).SetUnlinkOnClose(false)
+	listener.Close()
+	_, err = os.Stat(file)
+	assert.Nil(t, err)
+	listener, err = Listen(`unix://`+file, 0)
+	assert.Nil(t, err)
+	listener.Close()
+}
+
+func TestServerConfig_Listeners(t *testing.T) {
+	serverConfig := newTestingServerConfig()
+	serverConfig.Host = `127.0.0.1:0`
+	serverConfig.Listen = []string{`tcp://127.0.0.1:0`}
+
+	listeners, err := serverConfig.Listeners()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(listeners))
+	closeListeners(listeners)
+}
+
+func TestInheritedListeners(t *testing.T) {
+	listeners, err := InheritedListeners()
+	assert.Nil(t, err)
+	assert.Nil(t, listeners)
+
+	// The activation belongs to another process
+	os.Setenv(`LISTEN_PID`, strconv.Itoa(os.Getpid()+1))
+	os.Setenv(`LISTEN_FDS`, `1`)
+	listeners, err = InheritedListeners()
+	assert.Nil(t, err)
+	assert.Nil(t, listeners)
+	assert.Equal(t, ``, os.Getenv(`LISTEN_FDS`))
+}
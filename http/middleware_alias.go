@@ -0,0 +1,149 @@
+package http
+
+import (
+	"fmt"
+	"github.com/firmeve/firmeve/kernel/contract"
+	"strings"
+	"sync"
+)
+
+type (
+	// Create the middleware handler with the alias parameters, `throttle:60,1` calls the factory with "60" and "1"
+	MiddlewareFactory func(params ...string) contract.ContextHandler
+
+	// Middleware resolved through the container, so that fields with the `inject` tag are injected
+	Middleware interface {
+		Handle(c contract.Context, params ...string)
+	}
+
+	middlewareAliases struct {
+		aliases  map[string]interface{}
+		groups   map[string][]string
+		resolved map[string]contract.ContextHandler
+		mutex    sync.RWMutex
+	}
+)
+
+// Register a middleware alias, the middleware is a MiddlewareFactory, a contract.ContextHandler
+// or a Middleware prototype such as new(AuthMiddleware)
+func (r *Router) AliasMiddleware(name string, middleware interface{}) *Router {
+	r.mustNotFrozen()
+	switch middleware.(type) {
+	case MiddlewareFactory, func(params ...string) contract.ContextHandler,
+		contract.ContextHandler, func(c contract.Context), Middleware:
+	default:
+		panic(fmt.Errorf("unsupported middleware type %T", middleware))
+	}
+
+	r.middlewareAliases.mutex.Lock()
+	defer r.middlewareAliases.mutex.Unlock()
+	r.middlewareAliases.aliases[name] = middleware
+	r.middlewareAliases.resolved = make(map[string]contract.ContextHandler, 0)
+
+	return r
+}
+
+// Register a named group of middleware aliases, e.g. `web` or `api`.
+// The aliases are checked when the router is frozen, groups including themselves panic at once
+func (r *Router) MiddlewareGroup(name string, middleware ...string) *Router {
+	r.mustNotFrozen()
+	r.middlewareAliases.mutex.Lock()
+	defer r.middlewareAliases.mutex.Unlock()
+	group := append(r.middlewareAliases.groups[name], middleware...)
+	if path := r.middlewareAliases.cycle([]string{name}, group); path != nil {
+		panic(fmt.Errorf("the middleware group %s is cyclic: %s", name, strings.Join(path, ` -> `)))
+	}
+	r.middlewareAliases.groups[name] = group
+
+	return r
+}
+
+// Resolve middleware aliases and groups into handlers
+func (r *Router) resolveMiddleware(names []string) []contract.ContextHandler {
+	handlers := make([]contract.ContextHandler, 0, len(names))
+	for _, name := range names {
+		r.middlewareAliases.mutex.RLock()
+		group, isGroup := r.middlewareAliases.groups[name]
+		r.middlewareAliases.mutex.RUnlock()
+
+		if isGroup {
+			handlers = append(handlers, r.resolveMiddleware(group)...)
+		} else {
+			handlers = append(handlers, r.resolveAlias(name))
+		}
+	}
+
+	return handlers
+}
+
+func (r *Router) resolveAlias(alias string) contract.ContextHandler {
+	r.middlewareAliases.mutex.RLock()
+	handler, ok := r.middlewareAliases.resolved[alias]
+	r.middlewareAliases.mutex.RUnlock()
+	if ok {
+		return handler
+	}
+
+	r.middlewareAliases.mutex.Lock()
+	defer r.middlewareAliases.mutex.Unlock()
+
+	name, params := parseMiddlewareAlias(alias)
+	middleware, ok := r.middlewareAliases.aliases[name]
+	if !ok {
+		panic(fmt.Errorf("the middleware %s does not exist", name))
+	}
+
+	switch v := middleware.(type) {
+	case MiddlewareFactory:
+		handler = v(params...)
+	case func(params ...string) contract.ContextHandler:
+		handler = v(params...)
+	case contract.ContextHandler:
+		handler = v
+	case func(c contract.Context):
+		handler = v
+	case Middleware:
+		instance := r.Firmeve.Make(v).(Middleware)
+		handler = func(c contract.Context) {
+			instance.Handle(c, params...)
+		}
+	}
+	r.middlewareAliases.resolved[alias] = handler
+
+	return handler
+}
+
+// The path from the group back to a group of the path, nil without cycle
+func (m *middlewareAliases) cycle(path []string, names []string) []string {
+	for _, name := range names {
+		if inStrings(name, path) {
+			return append(path, name)
+		}
+
+		if group, ok := m.groups[name]; ok {
+			if cycle := m.cycle(append(path[:len(path):len(path)], name), group); cycle != nil {
+				return cycle
+			}
+		}
+	}
+
+	return nil
+}
+
+// Split `throttle:60,1` into the alias name and parameters
+func parseMiddlewareAlias(alias string) (string, []string) {
+	i := strings.Index(alias, `:`)
+	if i == -1 {
+		return alias, nil
+	}
+
+	return alias[:i], strings.Split(alias[i+1:], `,`)
+}
+
+func newMiddlewareAliases() *middlewareAliases {
+	return &middlewareAliases{
+		aliases:  make(map[string]interface{}, 0),
+		groups:   make(map[string][]string, 0),
+		resolved: make(map[string]contract.ContextHandler, 0),
+	}
+}
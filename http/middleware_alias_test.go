@@ -0,0 +1,102 @@
+package http
+
+import (
+	"github.com/firmeve/firmeve/kernel/contract"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type (
+	mockPrefix struct {
+		Value string
+	}
+
+	mockMiddleware struct {
+		Prefix *mockPrefix `inject:"mock.prefix"`
+	}
+)
+
+func (m *mockMiddleware) Handle(c contract.Context, params ...string) {
+	c.Protocol().Write([]byte(m.Prefix.Value + strings.Join(params, `-`) + `,`))
+	c.Next()
+}
+
+func TestParseMiddlewareAlias(t *testing.T) {
+	name, params := parseMiddlewareAlias(`throttle:60,1`)
+	assert.Equal(t, `throttle`, name)
+	assert.Equal(t, []string{`60`, `1`}, params)
+
+	name, params = parseMiddlewareAlias(`auth`)
+	assert.Equal(t, `auth`, name)
+	assert.Nil(t, params)
+}
+
+func TestRouter_AliasMiddleware(t *testing.T) {
+	router := newTestingRouter()
+	router.Firmeve.Bind(`mock.prefix`, &mockPrefix{Value: `injected:`})
+
+	router.AliasMiddleware(`auth`, writeHandler(`auth,`))
+	router.AliasMiddleware(`throttle`, MiddlewareFactory(func(params ...string) contract.ContextHandler {
+		return writeHandler(`throttle:` + strings.Join(params, `/`) + `,`)
+	}))
+	router.AliasMiddleware(`container`, new(mockMiddleware))
+	router.MiddlewareGroup(`api`, `throttle:60,1`, `auth`)
+
+	router.GET(`/alias`, writeHandler(`handler`)).Middleware(`auth`, `container:a,b`).Before(writeHandler(`before,`))
+	v1 := router.Group(`/v1`).Middleware(`api`)
+	v1.GET(`/group`, writeHandler(`handler`)).Middleware(`container`)
+	v1.Group(`/nested`).GET(`/group`, writeHandler(`handler`))
+
+	assert.Equal(t, `auth,injected:a-b,before,handler`, serveTestingRequest(router, http.MethodGet, `/alias`).Body.String())
+	assert.Equal(t, `throttle:60/1,auth,injected:,handler`, serveTestingRequest(router, http.MethodGet, `/v1/group`).Body.String())
+	assert.Equal(t, `throttle:60/1,auth,handler`, serveTestingRequest(router, http.MethodGet, `/v1/nested/group`).Body.String())
+
+	router.GET(`/unknown`, writeHandler(`handler`)).Middleware(`unknown`)
+	assert.Panics(t, func() {
+		serveTestingRequest(router, http.MethodGet, `/unknown`)
+	})
+	assert.Panics(t, func() {
+		router.AliasMiddleware(`invalid`, `string`)
+	})
+}
+
+func TestRouter_AliasMiddleware_Unknown(t *testing.T) {
+	router := newTestingRouter()
+	router.MiddlewareGroup(`web`, `session`)
+	router.GET(`/`, writeHandler(`home`)).Middleware(`web`)
+
+	assert.Equal(t, `the middleware session does not exist`, recoverTestingError(func() {
+		router.Freeze()
+	}))
+	assert.False(t, router.IsFrozen())
+
+	router.AliasMiddleware(`session`, writeHandler(`session,`))
+	assert.Equal(t, `session,home`, serveTestingRequest(router.Freeze(), http.MethodGet, `/`).Body.String())
+}
+
+func TestRouter_MiddlewareGroup_Cyclic(t *testing.T) {
+	router := newTestingRouter()
+	assert.Equal(t, `the middleware group web is cyclic: web -> web`, recoverTestingError(func() {
+		router.MiddlewareGroup(`web`, `session`, `web`)
+	}))
+
+	router.MiddlewareGroup(`web`, `session`, `api`)
+	router.MiddlewareGroup(`api`, `throttle`)
+	router.MiddlewareGroup(`auth`, `web`)
+	assert.Equal(t, `the middleware group api is cyclic: api -> auth -> web -> api`, recoverTestingError(func() {
+		router.MiddlewareGroup(`api`, `auth`)
+	}))
+}
+
+func recoverTestingError(f func()) (message string) {
+	defer func() {
+		if err, ok := recover().(error); ok {
+			message = err.Error()
+		}
+	}()
+	f()
+
+	return
+}
@@ -1,6 +1,8 @@
 package http
 
 import (
+	"github.com/firmeve/firmeve/kernel"
+	"github.com/firmeve/firmeve/kernel/contract"
 	logging "github.com/firmeve/firmeve/logger"
 	testing2 "github.com/firmeve/firmeve/testing"
 	"github.com/kataras/iris/core/errors"
@@ -10,14 +12,13 @@ import (
 
 func TestRecovery(t *testing.T) {
 	firmeve := testing2.TestingModeFirmeve()
-	firmeve.Register(new(logging.Provider),true)
+	firmeve.Register(new(logging.Provider), true)
 	req := testing2.NewMockRequest(http.MethodPost, "/?query=queryValue", "").Request
-	req.Header.Set(`Content-Type`,MIMEPOSTForm)
+	req.Header.Set(`Content-Type`, contract.HttpMimeForm)
+	req.Header.Set(`Accept`, contract.HttpMimeJson)
 	req.ParseMultipartForm(32 << 20)
-
-	c := newContext(firmeve, testing2.NewMockResponseWriter(), req, func(c *Context) {
+	c := kernel.NewContext(firmeve, NewHttp(req, testing2.NewMockResponseWriter()), Recovery, func(c contract.Context) {
 		panic(errors.New(`testing error`))
 	})
-
-	Recovery(c)
-}
\ No newline at end of file
+	c.Next()
+}
@@ -0,0 +1,77 @@
+package http
+
+import (
+	"fmt"
+	"github.com/firmeve/firmeve/database"
+	"github.com/firmeve/firmeve/kernel"
+	"github.com/firmeve/firmeve/kernel/contract"
+	"github.com/jinzhu/gorm"
+	"net/http"
+	"reflect"
+)
+
+type (
+	// Resolve the route parameter value into a model, a nil model is rendered as not found
+	ModelResolver func(c contract.Context, value string) (interface{}, error)
+)
+
+// Bind the route parameter to a model. The model is either a gorm model prototype such as &User{},
+// loaded by primary key through the default database connection, or a ModelResolver.
+// The loaded model is stored as the context entity named by the parameter
+func (r *Router) Model(param string, model interface{}) *Router {
+	r.mustNotFrozen()
+	switch v := model.(type) {
+	case ModelResolver:
+		r.models[param] = v
+	case func(c contract.Context, value string) (interface{}, error):
+		r.models[param] = v
+	default:
+		if reflect.TypeOf(model).Kind() != reflect.Ptr || reflect.TypeOf(model).Elem().Kind() != reflect.Struct {
+			panic(fmt.Errorf("the model %T must be a pointer to struct", model))
+		}
+		r.models[param] = r.primaryKeyResolver(reflect.TypeOf(model).Elem())
+	}
+
+	return r
+}
+
+func (r *Router) primaryKeyResolver(reflectType reflect.Type) ModelResolver {
+	return func(c contract.Context, value string) (interface{}, error) {
+		model := reflect.New(reflectType).Interface()
+		db := r.Firmeve.Get(`db`).(*database.DB).ConnectionDefault()
+		scope := db.NewScope(model)
+		// The value is always a bound parameter, gorm treats inline string conditions as sql
+		err := db.Where(fmt.Sprintf("%s.%s = ?", scope.QuotedTableName(), scope.Quote(scope.PrimaryKey())), value).First(model).Error
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, nil
+		}
+
+		return model, err
+	}
+}
+
+// Load the bound models of the route parameters before the route handlers run
+func (r *Router) bindModels(c contract.Context) {
+	protocol := c.Protocol().(contract.HttpProtocol)
+	for param, value := range protocol.Params() {
+		resolver, ok := r.models[param]
+		if !ok {
+			continue
+		}
+
+		model, err := resolver(c, value)
+		if err != nil {
+			c.Error(http.StatusInternalServerError, err)
+			c.Abort()
+			return
+		} else if model == nil {
+			c.Error(http.StatusNotFound, kernel.Errorf("%s %s not found", param, value))
+			c.Abort()
+			return
+		}
+
+		c.AddEntity(param, model)
+	}
+
+	c.Next()
+}
@@ -0,0 +1,91 @@
+package http
+
+import (
+	"github.com/firmeve/firmeve/config"
+	"github.com/firmeve/firmeve/database"
+	"github.com/firmeve/firmeve/kernel/contract"
+	"github.com/firmeve/firmeve/support/path"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type mockUser struct {
+	ID   uint
+	Name string
+}
+
+func newTestingModelRouter(t *testing.T) (*Router, func()) {
+	dir, err := ioutil.TempDir(``, `firmeve`)
+	assert.Nil(t, err)
+
+	databaseConfig := config.New(path.RunRelative(configPath)).Item(`database`)
+	databaseConfig.Set(`default`, `sqlite3`)
+	databaseConfig.Set(`connections.sqlite3.addr`, filepath.Join(dir, `test.db`))
+	db := database.New(databaseConfig)
+	db.ConnectionDefault().AutoMigrate(&mockUser{})
+	db.ConnectionDefault().Create(&mockUser{ID: 1, Name: `simon`})
+
+	router := newTestingRouter()
+	router.Firmeve.Bind(`db`, db)
+
+	return router, func() {
+		db.CloseDefault()
+		os.RemoveAll(dir)
+	}
+}
+
+func serveTestingJSONRequest(router *Router, method, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set(`Accept`, contract.HttpMimeJson)
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRouter_Model(t *testing.T) {
+	router, clean := newTestingModelRouter(t)
+	defer clean()
+
+	router.Model(`user`, &mockUser{})
+	router.GET(`/users/:user`, func(c contract.Context) {
+		c.Protocol().Write([]byte(c.Entity(`user`).Value.(*mockUser).Name))
+		c.Next()
+	})
+
+	w := serveTestingJSONRequest(router, http.MethodGet, `/users/1`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `simon`, w.Body.String())
+
+	w = serveTestingJSONRequest(router, http.MethodGet, `/users/2`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// the value is a bound parameter
+	w = serveTestingJSONRequest(router, http.MethodGet, `/users/1%20or%201=1`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	assert.Panics(t, func() {
+		router.Model(`invalid`, mockUser{})
+	})
+}
+
+func TestRouter_Model_Resolver(t *testing.T) {
+	router := newTestingRouter()
+	router.Model(`slug`, ModelResolver(func(c contract.Context, value string) (interface{}, error) {
+		if value == `firmeve` {
+			return &mockUser{Name: value}, nil
+		}
+		return nil, nil
+	}))
+	router.GET(`/posts/:slug`, func(c contract.Context) {
+		c.Protocol().Write([]byte(c.Entity(`slug`).Value.(*mockUser).Name))
+		c.Next()
+	})
+
+	assert.Equal(t, `firmeve`, serveTestingJSONRequest(router, http.MethodGet, `/posts/firmeve`).Body.String())
+	assert.Equal(t, http.StatusNotFound, serveTestingJSONRequest(router, http.MethodGet, `/posts/other`).Code)
+}
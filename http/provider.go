@@ -18,5 +18,8 @@ func (p *Provider) Register() {
 }
 
 func (p *Provider) Boot() {
-
+	// Readable responses while developing
+	if p.Firmeve.IsDevelopment() {
+		p.Firmeve.Get(`http.router`).(*Router).SetPrettyJSON(true)
+	}
 }
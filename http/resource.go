@@ -0,0 +1,226 @@
+package http
+
+import (
+	"github.com/firmeve/firmeve/kernel/contract"
+	"github.com/firmeve/firmeve/support"
+	strings2 "github.com/firmeve/firmeve/support/strings"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+type (
+	Resource struct {
+		name   string
+		param  string
+		routes map[string]*Route
+	}
+
+	resourceOption struct {
+		only   []string
+		except []string
+		param  string
+	}
+
+	resourceAction struct {
+		name    string
+		methods []string
+		path    string
+	}
+
+	routeCreator func(methods []string, path string, handler contract.ContextHandler) *Route
+)
+
+var (
+	// create is registered as a child of show, httprouter does not allow `/create` next to `/:param`
+	resourceActions = []resourceAction{
+		{name: `index`, methods: []string{http.MethodGet}, path: ``},
+		{name: `store`, methods: []string{http.MethodPost}, path: ``},
+		{name: `show`, methods: []string{http.MethodGet}, path: `/:param`},
+		{name: `create`, methods: []string{http.MethodGet}, path: `/create`},
+		{name: `edit`, methods: []string{http.MethodGet}, path: `/:param/edit`},
+		{name: `update`, methods: []string{http.MethodPut, http.MethodPatch}, path: `/:param`},
+		{name: `destroy`, methods: []string{http.MethodDelete}, path: `/:param`},
+	}
+)
+
+// Only register the given resource actions
+func ResourceOnly(actions ...string) support.Option {
+	return func(object support.Object) {
+		object.(*resourceOption).only = actions
+	}
+}
+
+// Register all resource actions except the given ones
+func ResourceExcept(actions ...string) support.Option {
+	return func(object support.Object) {
+		object.(*resourceOption).except = actions
+	}
+}
+
+// The route parameter name of the resource, the default is the singular of the last path segment
+func ResourceParam(param string) support.Option {
+	return func(object support.Object) {
+		object.(*resourceOption).param = param
+	}
+}
+
+// Map the index/create/store/show/edit/update/destroy methods of the controller to RESTful routes,
+// e.g. Resource("/users/:user/photos", new(PhotoController)) registers `users.photos.index` on GET /users/:user/photos.
+// The controller is resolved through the container, so that fields with the `inject` tag are injected
+func (r *Router) Resource(path string, controller interface{}, options ...support.Option) *Resource {
+	return r.resource(``, path, controller, r.createRoute, func(route *Route) *Route {
+		return route
+	}, options...)
+}
+
+func (g *Group) Resource(path string, controller interface{}, options ...support.Option) *Resource {
+	return g.router.resource(g.prefix, path, controller, g.createRoute, g.apply, options...)
+}
+
+// Get the route of the action, nil when the action is not registered
+func (r *Resource) Route(action string) *Route {
+	return r.routes[action]
+}
+
+func (r *Resource) Name() string {
+	return r.name
+}
+
+func (r *Resource) Param() string {
+	return r.param
+}
+
+func (r *Resource) Before(handlers ...contract.ContextHandler) *Resource {
+	for _, route := range r.routes {
+		route.Before(handlers...)
+	}
+
+	return r
+}
+
+func (r *Resource) After(handlers ...contract.ContextHandler) *Resource {
+	for _, route := range r.routes {
+		route.After(handlers...)
+	}
+
+	return r
+}
+
+func (r *Resource) Middleware(names ...string) *Resource {
+	for _, route := range r.routes {
+		route.Middleware(names...)
+	}
+
+	return r
+}
+
+// The create function prefixes the path of the routes it registers
+func (r *Router) resource(prefix, path string, controller interface{}, create routeCreator, apply func(route *Route) *Route, options ...support.Option) *Resource {
+	// The prefix of the group keeps the names apart from the resources of other groups
+	name, lastSegment := resourceName(prefix + path)
+	option := support.ApplyOption(&resourceOption{
+		param: resourceSingular(lastSegment),
+	}, options...).(*resourceOption)
+
+	resource := &Resource{
+		name:   name,
+		param:  option.param,
+		routes: make(map[string]*Route, 0),
+	}
+
+	controllerValue := reflect.ValueOf(r.Firmeve.Make(controller))
+	var show *Route
+	for _, action := range resourceActions {
+		handler, ok := resourceHandler(controllerValue, action.name)
+		enabled := ok && option.enabled(action.name)
+		actionPath := path + strings.Replace(action.path, `:param`, `:`+option.param, 1)
+
+		// httprouter needs the show route as the parent of create
+		if action.name == `show` && !enabled && option.enabled(`create`) {
+			if _, ok := resourceHandler(controllerValue, `create`); ok {
+				show = create(action.methods, actionPath, r.notFound)
+			}
+			continue
+		}
+
+		if !enabled {
+			continue
+		}
+
+		var route *Route
+		if action.name == `create` {
+			route = apply(newRoute(r, prefix+actionPath, handler))
+			show.children[`create`] = route
+		} else {
+			route = create(action.methods, actionPath, handler)
+		}
+
+		route.Name(strings.Join([]string{name, action.name}, `.`))
+		resource.routes[action.name] = route
+		if action.name == `show` {
+			show = route
+		}
+	}
+
+	return resource
+}
+
+func (r *resourceOption) enabled(action string) bool {
+	if len(r.only) > 0 {
+		return inStrings(action, r.only)
+	}
+
+	return !inStrings(action, r.except)
+}
+
+func resourceHandler(controller reflect.Value, action string) (contract.ContextHandler, bool) {
+	method := controller.MethodByName(strings2.UcFirst(action))
+	if !method.IsValid() {
+		return nil, false
+	}
+
+	if handler, ok := method.Interface().(func(c contract.Context)); ok {
+		return handler, true
+	}
+
+	return nil, false
+}
+
+// `/users/:user/photos` is named `users.photos` with the last static segment `photos`
+func resourceName(path string) (string, string) {
+	segments := make([]string, 0)
+	for _, segment := range strings.Split(path, `/`) {
+		if segment != `` && segment[0] != ':' && segment[0] != '*' {
+			segments = append(segments, segment)
+		}
+	}
+
+	if len(segments) == 0 {
+		return ``, ``
+	}
+
+	return strings.Join(segments, `.`), segments[len(segments)-1]
+}
+
+func resourceSingular(name string) string {
+	if strings.HasSuffix(name, `ies`) {
+		return strings.TrimSuffix(name, `ies`) + `y`
+	} else if strings.HasSuffix(name, `ses`) || strings.HasSuffix(name, `xes`) {
+		return strings.TrimSuffix(name, `es`)
+	} else if strings.HasSuffix(name, `s`) {
+		return strings.TrimSuffix(name, `s`)
+	}
+
+	return name
+}
+
+func inStrings(value string, values []string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,98 @@
+package http
+
+import (
+	"github.com/firmeve/firmeve/kernel/contract"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+type (
+	mockPhotoController struct {
+		Prefix *mockPrefix `inject:"mock.prefix"`
+	}
+
+	mockCommentController struct {
+	}
+)
+
+func (m *mockPhotoController) write(c contract.Context, action string) {
+	protocol := c.Protocol().(contract.HttpProtocol)
+	c.Protocol().Write([]byte(m.Prefix.Value + action + `:` + protocol.Param(`photo`)))
+	c.Next()
+}
+
+func (m *mockPhotoController) Index(c contract.Context)   { m.write(c, `index`) }
+func (m *mockPhotoController) Create(c contract.Context)  { m.write(c, `create`) }
+func (m *mockPhotoController) Store(c contract.Context)   { m.write(c, `store`) }
+func (m *mockPhotoController) Show(c contract.Context)    { m.write(c, `show`) }
+func (m *mockPhotoController) Edit(c contract.Context)    { m.write(c, `edit`) }
+func (m *mockPhotoController) Update(c contract.Context)  { m.write(c, `update`) }
+func (m *mockPhotoController) Destroy(c contract.Context) { m.write(c, `destroy`) }
+
+func (m *mockCommentController) Create(c contract.Context) {
+	c.Protocol().Write([]byte(`comment.create:` + c.Protocol().(contract.HttpProtocol).Param(`user`)))
+	c.Next()
+}
+
+func TestResourceName(t *testing.T) {
+	name, last := resourceName(`/users/:user/photos`)
+	assert.Equal(t, `users.photos`, name)
+	assert.Equal(t, `photos`, last)
+
+	assert.Equal(t, `photo`, resourceSingular(`photos`))
+	assert.Equal(t, `category`, resourceSingular(`categories`))
+	assert.Equal(t, `box`, resourceSingular(`boxes`))
+}
+
+func TestRouter_Resource(t *testing.T) {
+	router := newTestingRouter()
+	router.Firmeve.Bind(`mock.prefix`, &mockPrefix{Value: `photos.`})
+	resource := router.Resource(`/photos`, new(mockPhotoController)).Before(writeHandler(`before,`))
+	assert.Equal(t, `photos`, resource.Name())
+	assert.Equal(t, `photo`, resource.Param())
+	assert.Equal(t, `photos.index`, resource.Route(`index`).name)
+
+	cases := []struct {
+		method, path, body string
+	}{
+		{http.MethodGet, `/photos`, `before,photos.index:`},
+		{http.MethodGet, `/photos/create`, `before,photos.create:`},
+		{http.MethodPost, `/photos`, `before,photos.store:`},
+		{http.MethodGet, `/photos/1`, `before,photos.show:1`},
+		{http.MethodGet, `/photos/1/edit`, `before,photos.edit:1`},
+		{http.MethodPut, `/photos/1`, `before,photos.update:1`},
+		{http.MethodPatch, `/photos/1`, `before,photos.update:1`},
+		{http.MethodDelete, `/photos/1`, `before,photos.destroy:1`},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.body, serveTestingRequest(router, c.method, c.path).Body.String(), c.method+` `+c.path)
+	}
+}
+
+func TestRouter_Resource_Options(t *testing.T) {
+	router := newTestingRouter()
+	router.Firmeve.Bind(`mock.prefix`, &mockPrefix{})
+
+	resource := router.Resource(`/photos`, new(mockPhotoController), ResourceOnly(`index`, `show`), ResourceParam(`id`))
+	assert.Nil(t, resource.Route(`store`))
+	assert.Equal(t, `show:`, serveTestingRequest(router, http.MethodGet, `/photos/1`).Body.String()[:5])
+	assert.Equal(t, http.StatusMethodNotAllowed, serveTestingRequest(router, http.MethodPost, `/photos`).Code)
+
+	resource = router.Resource(`/videos`, new(mockPhotoController), ResourceExcept(`destroy`, `show`))
+	assert.Nil(t, resource.Route(`destroy`))
+	assert.Nil(t, resource.Route(`show`))
+	assert.Equal(t, `create:`, serveTestingRequest(router, http.MethodGet, `/videos/create`).Body.String())
+	assert.Equal(t, http.StatusNotFound, serveTestingRequest(router, http.MethodGet, `/videos/1`).Code)
+}
+
+func TestGroup_Resource_Nested(t *testing.T) {
+	router := newTestingRouter()
+	resource := router.Group(`/api`).Before(writeHandler(`group,`)).Resource(`/users/:user/comments`, new(mockCommentController))
+	assert.Equal(t, `api.users.comments`, resource.Name())
+	assert.Equal(t, `api.users.comments.create`, resource.Route(`create`).name)
+	assert.Nil(t, resource.Route(`index`))
+
+	assert.Equal(t, `group,comment.create:5`, serveTestingRequest(router, http.MethodGet, `/api/users/5/comments/create`).Body.String())
+	assert.Contains(t, serveTestingRequest(router, http.MethodGet, `/api/users/5/comments/1`).Body.String(), `404 page not found`)
+}
@@ -0,0 +1,142 @@
+package http
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// The environment variable holding the descriptor a restarted process writes to once it is serving
+	readyFdEnv = `FIRMEVE_READY_FD`
+)
+
+type (
+	fileListener interface {
+		net.Listener
+		File() (*os.File, error)
+	}
+)
+
+// Fork a new process of the current command which inherits the listeners through socket activation
+// and wait until it reports ready, the caller is responsible for draining the current server
+func Restart(listeners []net.Listener, timeout time.Duration) (*os.Process, error) {
+	files := make([]*os.File, 0, len(listeners)+1)
+	defer func() {
+		for i := range files {
+			files[i].Close()
+		}
+	}()
+
+	for i := range listeners {
+		listener, ok := listeners[i].(fileListener)
+		if !ok {
+			return nil, fmt.Errorf("listener %s can not be inherited", listeners[i].Addr().String())
+		}
+
+		file, err := listener.File()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	files = append(files, writer)
+
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command(executable, os.Args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = files
+	cmd.Env = append(restartEnv(os.Environ()),
+		`LISTEN_FDS=`+strconv.Itoa(len(listeners)),
+		readyFdEnv+`=`+strconv.Itoa(listenFdsStart+len(listeners)),
+	)
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	// Close the parent copy of the write end, so that the read fails if the child exits early
+	writer.Close()
+	files = files[:len(files)-1]
+
+	ready := make(chan error, 1)
+	go func() {
+		_, err := reader.Read(make([]byte, 1))
+		ready <- err
+	}()
+
+	select {
+	case err := <-ready:
+		if err != nil {
+			// The exit status of the process tells why it stopped
+			if exitErr := killProcess(cmd); exitErr != nil {
+				err = exitErr
+			}
+			return nil, fmt.Errorf("restarted process exited before ready: %w", err)
+		}
+	case <-time.After(timeout):
+		killProcess(cmd)
+		return nil, fmt.Errorf("restarted process not ready after %s", timeout)
+	}
+
+	// The new process owns the unix socket files now
+	for i := range listeners {
+		if v, ok := listeners[i].(*net.UnixListener); ok {
+			v.SetUnlinkOnClose(false)
+		}
+	}
+
+	return cmd.Process, nil
+}
+
+// Kill the process and wait for it, so that it does not remain a zombie
+func killProcess(cmd *exec.Cmd) error {
+	cmd.Process.Kill()
+	return cmd.Wait()
+}
+
+// Tell the parent process that started a graceful restart that we are serving
+func NotifyReady() error {
+	value := os.Getenv(readyFdEnv)
+	if value == `` {
+		return nil
+	}
+	os.Unsetenv(readyFdEnv)
+
+	fd, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+
+	file := os.NewFile(uintptr(fd), `ready`)
+	defer file.Close()
+	_, err = file.Write([]byte{1})
+
+	return err
+}
+
+func restartEnv(env []string) []string {
+	newEnv := make([]string, 0, len(env))
+	for _, v := range env {
+		if strings.HasPrefix(v, `LISTEN_`) || strings.HasPrefix(v, readyFdEnv+`=`) {
+			continue
+		}
+		newEnv = append(newEnv, v)
+	}
+
+	return newEnv
+}
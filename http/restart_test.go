@@ -0,0 +1,136 @@
+package http
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net"
+	"os"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+)
+
+type mockListener struct {
+	net.Listener
+}
+
+func TestRestart_NotInheritable(t *testing.T) {
+	listener, err := net.Listen(`tcp`, `127.0.0.1:0`)
+	assert.Nil(t, err)
+	defer listener.Close()
+
+	_, err = Restart([]net.Listener{&mockListener{listener}}, time.Second)
+	assert.NotNil(t, err)
+}
+
+// The restarted test binary runs the helper only, which exits, hangs or serves the inherited listener as the parent asks
+func TestRestart_HelperProcess(t *testing.T) {
+	switch os.Getenv(`FIRMEVE_RESTART_HELPER`) {
+	case `exit`:
+		os.Exit(3)
+	case `hang`:
+		time.Sleep(time.Minute)
+	case `ready`:
+		listeners, err := InheritedListeners()
+		if err != nil || len(listeners) != 1 {
+			os.Exit(4)
+		}
+		if err := NotifyReady(); err != nil {
+			os.Exit(5)
+		}
+		conn, err := listeners[0].Accept()
+		if err != nil {
+			os.Exit(6)
+		}
+		conn.Write([]byte(`restarted`))
+		conn.Close()
+		os.Exit(0)
+	}
+}
+
+func TestRestart(t *testing.T) {
+	listener, err := net.Listen(`tcp`, `127.0.0.1:0`)
+	assert.Nil(t, err)
+	defer listener.Close()
+
+	args := os.Args
+	os.Args = []string{args[0], `-test.run=TestRestart_HelperProcess`}
+	defer func() {
+		os.Args = args
+		os.Unsetenv(`FIRMEVE_RESTART_HELPER`)
+	}()
+
+	os.Setenv(`FIRMEVE_RESTART_HELPER`, `ready`)
+	process, err := Restart([]net.Listener{listener}, 10*time.Second)
+	assert.Nil(t, err)
+	if !assert.NotNil(t, process) {
+		return
+	}
+	assert.NotEqual(t, os.Getpid(), process.Pid)
+
+	// the parent does not accept, the connection is served by the new process on the inherited listener
+	conn, err := net.DialTimeout(`tcp`, listener.Addr().String(), time.Second)
+	assert.Nil(t, err)
+	conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	content, err := ioutil.ReadAll(conn)
+	conn.Close()
+	assert.Nil(t, err)
+	assert.Equal(t, `restarted`, string(content))
+
+	state, err := process.Wait()
+	assert.Nil(t, err)
+	assert.True(t, state.Success())
+}
+
+func TestRestart_NotReady(t *testing.T) {
+	listener, err := net.Listen(`tcp`, `127.0.0.1:0`)
+	assert.Nil(t, err)
+	defer listener.Close()
+
+	args := os.Args
+	os.Args = []string{args[0], `-test.run=TestRestart_HelperProcess`}
+	defer func() {
+		os.Args = args
+		os.Unsetenv(`FIRMEVE_RESTART_HELPER`)
+	}()
+
+	os.Setenv(`FIRMEVE_RESTART_HELPER`, `exit`)
+	_, err = Restart([]net.Listener{listener}, 10*time.Second)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), `exit status 3`)
+
+	os.Setenv(`FIRMEVE_RESTART_HELPER`, `hang`)
+	start := time.Now()
+	_, err = Restart([]net.Listener{listener}, 200*time.Millisecond)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), `not ready`)
+	assert.True(t, time.Since(start) < 10*time.Second)
+}
+
+func TestNotifyReady(t *testing.T) {
+	assert.Nil(t, NotifyReady())
+
+	reader, writer, err := os.Pipe()
+	assert.Nil(t, err)
+	defer reader.Close()
+
+	// NotifyReady owns and closes the descriptor
+	fd, err := syscall.Dup(int(writer.Fd()))
+	assert.Nil(t, err)
+	writer.Close()
+
+	os.Setenv(readyFdEnv, strconv.Itoa(fd))
+	assert.Nil(t, NotifyReady())
+	assert.Equal(t, ``, os.Getenv(readyFdEnv))
+
+	p := make([]byte, 1)
+	n, err := reader.Read(p)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+}
+
+func TestRestartEnv(t *testing.T) {
+	env := restartEnv([]string{`PATH=/bin`, `LISTEN_FDS=2`, `LISTEN_PID=1`, readyFdEnv + `=5`})
+	assert.Equal(t, []string{`PATH=/bin`}, env)
+}
@@ -1,39 +1,113 @@
 package http
 
-import "github.com/firmeve/firmeve/kernel/contract"
+import (
+	"github.com/firmeve/firmeve/kernel/contract"
+	"github.com/julienschmidt/httprouter"
+	"regexp"
+)
 
 type Route struct {
+	router         *Router
 	path           string
 	name           string
 	beforeHandlers []contract.ContextHandler
 	afterHandlers  []contract.ContextHandler
 	handler        contract.ContextHandler
+	middleware     []string
+	patterns       map[string]*regexp.Regexp
+	// The full handlers compiled by Router.Freeze
+	compiled []contract.ContextHandler
+	// Routes dispatched by the value of the last path parameter, such as the create action of a resource
+	children map[string]*Route
+	// 0 uses the limit of the router, a negative size disables the limit
+	maxBodySize int64
 }
 
+// The name of the route for Router.URL, the last route given a name takes it
 func (r *Route) Name(name string) *Route {
+	r.mustNotFrozen()
+	if r.router != nil {
+		r.router.nameRoute(name, r)
+	}
 	r.name = name
 	return r
 }
 
 func (r *Route) Before(handlers ...contract.ContextHandler) *Route {
+	r.mustNotFrozen()
 	r.beforeHandlers = append(r.beforeHandlers, handlers...)
 	return r
 }
 
 func (r *Route) After(handlers ...contract.ContextHandler) *Route {
+	r.mustNotFrozen()
 	r.afterHandlers = append(r.afterHandlers, handlers...)
 	return r
 }
 
+// Constrain the route parameter with a regular expression, e.g. Where("id", "[0-9]+").
+// Requests not matching the pattern are handled by the NotFound handler
+func (r *Route) Where(param string, pattern string) *Route {
+	r.mustNotFrozen()
+	r.patterns[param] = compilePattern(pattern)
+	return r
+}
+
+// Middleware aliases or groups registered on the router, e.g. Middleware("auth", "throttle:60,1").
+// They run ahead of the before handlers
+func (r *Route) Middleware(names ...string) *Route {
+	r.mustNotFrozen()
+	r.middleware = append(r.middleware, names...)
+	return r
+}
+
+// The max size of the request body of the route, overriding the limit of the router, e.g. MaxBodySize(100 << 20) for uploads
+func (r *Route) MaxBodySize(size int64) *Route {
+	r.mustNotFrozen()
+	r.maxBodySize = size
+	return r
+}
+
+// The compiled handlers of a frozen router no longer change, so the route can not either
+func (r *Route) mustNotFrozen() {
+	if r.router != nil {
+		r.router.mustNotFrozen()
+	}
+}
+
 func (r *Route) Handlers() []contract.ContextHandler {
-	return append(append(r.beforeHandlers, r.handler), r.afterHandlers...)
+	handlers := make([]contract.ContextHandler, 0, len(r.beforeHandlers)+len(r.afterHandlers)+1)
+	handlers = append(handlers, r.beforeHandlers...)
+	handlers = append(handlers, r.handler)
+	return append(handlers, r.afterHandlers...)
+}
+
+// Find the child route matching the last parameter, the parameter is consumed by the child
+func (r *Route) child(params httprouter.Params) (*Route, httprouter.Params) {
+	if len(r.children) == 0 || len(params) == 0 {
+		return r, params
+	}
+
+	if child, ok := r.children[params[len(params)-1].Value]; ok {
+		return child, params[:len(params)-1]
+	}
+
+	return r, params
+}
+
+func compilePattern(pattern string) *regexp.Regexp {
+	return regexp.MustCompile(`^(?:` + pattern + `)$`)
 }
 
-func newRoute(path string, handler contract.ContextHandler) *Route {
+func newRoute(router *Router, path string, handler contract.ContextHandler) *Route {
 	return &Route{
+		router:         router,
 		path:           path,
 		handler:        handler,
 		beforeHandlers: make([]contract.ContextHandler, 0),
 		afterHandlers:  make([]contract.ContextHandler, 0),
+		middleware:     make([]string, 0),
+		children:       make(map[string]*Route, 0),
+		patterns:       make(map[string]*regexp.Regexp, 0),
 	}
 }
@@ -1,69 +1,185 @@
 package http
 
 import (
+	"fmt"
 	"github.com/firmeve/firmeve/kernel"
 	"github.com/firmeve/firmeve/kernel/contract"
 	"github.com/julienschmidt/httprouter"
 	"net/http"
+	"regexp"
 	"strings"
 )
 
+var (
+	// CONNECT is meant for proxies and TRACE would allow cross-site tracing, Match registers them explicitly
+	anyMethods = []string{
+		http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+		http.MethodPatch, http.MethodDelete, http.MethodOptions,
+	}
+)
+
 type Router struct {
-	Firmeve   contract.Application
-	router    *httprouter.Router
-	routes    map[string]*Route
-	routeKeys []string
+	Firmeve           contract.Application
+	router            *httprouter.Router
+	routes            map[string]*Route
+	names             map[string]*Route
+	routeKeys         []string
+	beforeMiddleware  []*GlobalMiddleware
+	afterMiddleware   []*GlobalMiddleware
+	middlewareAliases *middlewareAliases
+	models            map[string]ModelResolver
+	patterns          map[string]*regexp.Regexp
+	domains           map[string]*domain
+	domainPatterns    []string
+	fallbacks         map[string]*Route
+	frozen            bool
+	maxMemory         int64
+	maxBodySize       int64
+	prettyJSON        bool
+	notFoundHandler   contract.ContextHandler
+	staticRoot        bool
 }
 
 func New(firmeve contract.Application) *Router {
 	return &Router{
-		Firmeve:   firmeve,
-		router:    httprouter.New(),
-		routes:    make(map[string]*Route, 0),
-		routeKeys: make([]string, 0),
+		Firmeve:           firmeve,
+		router:            httprouter.New(),
+		routes:            make(map[string]*Route, 0),
+		names:             make(map[string]*Route, 0),
+		routeKeys:         make([]string, 0),
+		beforeMiddleware:  make([]*GlobalMiddleware, 0),
+		afterMiddleware:   make([]*GlobalMiddleware, 0),
+		middlewareAliases: newMiddlewareAliases(),
+		models:            make(map[string]ModelResolver, 0),
+		patterns:          make(map[string]*regexp.Regexp, 0),
+		domains:           make(map[string]*domain, 0),
+		domainPatterns:    make([]string, 0),
+		fallbacks:         make(map[string]*Route, 0),
+		maxMemory:         defaultMaxMemory,
 	}
 }
 
 func (r *Router) GET(path string, handler contract.ContextHandler) *Route {
-	return r.createRoute(http.MethodGet, path, handler)
+	return r.createRoute([]string{http.MethodGet}, path, handler)
 }
 
 func (r *Router) POST(path string, handler contract.ContextHandler) *Route {
-	return r.createRoute(http.MethodPost, path, handler)
+	return r.createRoute([]string{http.MethodPost}, path, handler)
 }
 
 func (r *Router) PUT(path string, handler contract.ContextHandler) *Route {
-	return r.createRoute(http.MethodPut, path, handler)
+	return r.createRoute([]string{http.MethodPut}, path, handler)
 }
 
 func (r *Router) PATCH(path string, handler contract.ContextHandler) *Route {
-	return r.createRoute(http.MethodPatch, path, handler)
+	return r.createRoute([]string{http.MethodPatch}, path, handler)
 }
 
 func (r *Router) DELETE(path string, handler contract.ContextHandler) *Route {
-	return r.createRoute(http.MethodDelete, path, handler)
+	return r.createRoute([]string{http.MethodDelete}, path, handler)
 }
 
 func (r *Router) OPTIONS(path string, handler contract.ContextHandler) *Route {
-	return r.createRoute(http.MethodOptions, path, handler)
+	return r.createRoute([]string{http.MethodOptions}, path, handler)
+}
+
+func (r *Router) HEAD(path string, handler contract.ContextHandler) *Route {
+	return r.createRoute([]string{http.MethodHead}, path, handler)
+}
+
+// Register the route for the methods of applications, GET, HEAD, POST, PUT, PATCH, DELETE and OPTIONS
+func (r *Router) Any(path string, handler contract.ContextHandler) *Route {
+	return r.createRoute(anyMethods, path, handler)
+}
+
+// Register the route for the given http methods
+func (r *Router) Match(methods []string, path string, handler contract.ContextHandler) *Route {
+	return r.createRoute(methods, path, handler)
+}
+
+// Global middleware which runs before the handlers of every route, including the routes registered before the call.
+// Global middleware runs in registration order ahead of group and route middleware
+func (r *Router) Use(handlers ...contract.ContextHandler) *GlobalMiddleware {
+	r.mustNotFrozen()
+	middleware := newGlobalMiddleware(r, handlers)
+	r.beforeMiddleware = append(r.beforeMiddleware, middleware)
+	return middleware
+}
+
+// Global middleware which runs after the handlers of every route, following group and route middleware
+func (r *Router) UseAfter(handlers ...contract.ContextHandler) *GlobalMiddleware {
+	r.mustNotFrozen()
+	middleware := newGlobalMiddleware(r, handlers)
+	r.afterMiddleware = append(r.afterMiddleware, middleware)
+	return middleware
 }
 
-// serve static files
-func (r *Router) Static(path string, root string) *Router {
-	r.router.ServeFiles(strings.Join([]string{path, `/*filepath`}, ``), http.Dir(root))
+// Constrain the parameter of every route with a regular expression, Route.Where takes precedence
+func (r *Router) Pattern(param string, pattern string) *Router {
+	r.mustNotFrozen()
+	r.patterns[param] = compilePattern(pattern)
 	return r
 }
 
+// The handler of the missing routes and of the missing static files
 func (r *Router) NotFound(handler contract.ContextHandler) *Router {
-	r.router.NotFound = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
-		kernel.NewContext(r.Firmeve, NewHttp(req, w), handler).Next()
-		//newContext(r.Firmeve, w, req, handler).Next()
-	})
+	r.mustNotFrozen()
+	r.notFoundHandler = handler
+	// The static files mounted at `/` keep serving the missing routes
+	if !r.staticRoot {
+		r.router.NotFound = r.fallback(`not_found`, handler)
+	}
+	return r
+}
+
+// The Allow header is set before the handler is called
+func (r *Router) MethodNotAllowed(handler contract.ContextHandler) *Router {
+	r.router.MethodNotAllowed = r.fallback(`method_not_allowed`, handler)
+	return r
+}
+
+// Compile the full handler slice of every route once, so that dispatching no longer assembles the handlers per request.
+// Registering routes or router middleware after the router is frozen panics, and so do unknown middleware aliases
+func (r *Router) Freeze() *Router {
+	if r.frozen {
+		return r
+	}
+
+	for _, route := range r.routes {
+		r.compile(route)
+	}
+	for _, route := range r.fallbacks {
+		route.compiled = r.handlers(``, route.Handlers()...)
+	}
+	r.frozen = true
+
+	return r
+}
+
+func (r *Router) IsFrozen() bool {
+	return r.frozen
+}
+
+// The max size of the request bodies, 0 or a negative size means no limit
+func (r *Router) SetMaxBodySize(size int64) *Router {
+	r.maxBodySize = size
+	return r
+}
+
+// Indent every json response of the router, the other protocols keep the compact json
+func (r *Router) SetPrettyJSON(pretty bool) *Router {
+	r.prettyJSON = pretty
+	return r
+}
+
+// The memory of parsing the multipart forms, the remaining files are stored in temporary files
+func (r *Router) SetMaxMultipartMemory(maxMemory int64) *Router {
+	r.maxMemory = maxMemory
 	return r
 }
 
 func (r *Router) Handler(method, path string, handler http.HandlerFunc) {
-	r.createRoute(method, path, func(c contract.Context) {
+	r.createRoute([]string{method}, path, func(c contract.Context) {
 		protocol := c.Protocol().(contract.HttpProtocol)
 		handler(protocol.ResponseWriter(), protocol.Request())
 	})
@@ -73,37 +189,194 @@ func (r *Router) HttpRouter() *httprouter.Router {
 	return r.router
 }
 
-//
 func (r *Router) Group(prefix string) *Group {
 	return newGroup(r).Prefix(prefix)
 }
 
-func (r *Router) createRoute(method string, path string, handler contract.ContextHandler) *Route {
-	key := r.routeKey(method, path)
-	r.routes[key] = newRoute(path, handler)
+func (r *Router) createRoute(methods []string, path string, handler contract.ContextHandler) *Route {
+	return r.createDomainRoute(nil, methods, path, handler)
+}
 
-	//Only http router
-	//r.router.Handler(method, path, r)
-	r.router.Handle(method, path, func(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
-		ctxParams := make(map[string]string, 0)
-		for _, param := range params {
-			ctxParams[param.Key] = param.Value
-		}
+// Register the route on the httprouter of the domain, nil is the router without domain
+func (r *Router) createDomainRoute(d *domain, methods []string, path string, handler contract.ContextHandler) *Route {
+	r.mustNotFrozen()
+	router, keyPath := r.router, path
+	if d != nil {
+		router, keyPath = d.router, d.pattern+path
+	}
 
-		ctx := kernel.NewContext(r.Firmeve, NewHttp(req, w), r.routes[key].Handlers()...)
-		//ctx := newContext(r.Firmeve, w, req, r.routes[key].Handlers()...).
-		//	SetParams(ctxParams).
-		//	SetRoute(r.routes[key])
+	route := newRoute(r, path, handler)
+	for _, method := range methods {
+		key := r.routeKey(method, keyPath)
+		r.routes[key] = route
 
-		r.Firmeve.Get(`event`).(contract.Event).Dispatch(`router.match`, map[string]interface{}{
-			`context`: ctx,
-			`route`:   r.routes[key],
+		//Only http router
+		//r.router.Handler(method, path, r)
+		router.Handle(method, path, func(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+			r.dispatch(route, params, w, req)
 		})
+	}
+
+	return route
+}
+
+func (r *Router) dispatch(route *Route, params httprouter.Params, w http.ResponseWriter, req *http.Request) {
+	route, params = route.child(params)
+	ctxParams := make(map[string]string, 0)
+	for _, param := range append(hostParams(req), params...) {
+		ctxParams[param.Key] = param.Value
+	}
+
+	if !r.matchPatterns(route, ctxParams) {
+		r.serveNotFound(w, req)
+		return
+	}
+
+	protocol := NewHttp(req, w).(*Http)
+	protocol.SetParams(ctxParams)
+	protocol.SetMaxMemory(r.maxMemory)
+	protocol.SetPrettyJSON(r.prettyJSON)
+	ctx := kernel.NewContext(r.Firmeve, protocol, r.compiledHandlers(route)...)
+	//ctx := newContext(r.Firmeve, w, req, r.routes[key].Handlers()...).
+	//	SetParams(ctxParams).
+	//	SetRoute(r.routes[key])
+
+	r.Firmeve.Get(`event`).(contract.Event).Dispatch(`router.match`, map[string]interface{}{
+		`context`: ctx,
+		`route`:   route,
+	})
+
+	if limit := r.bodyLimit(route); limit > 0 {
+		protocol.SetMaxBodySize(limit)
+	}
 
-		ctx.Next()
+	ctx.Next()
+}
+
+// Bodies declared too large are rejected after the global middleware, the others fail when the limit is read
+func (r *Router) limitBody(c contract.Context) {
+	if protocol, ok := c.Protocol().(*Http); ok && protocol.maxBodySize > 0 && protocol.request.ContentLength > protocol.maxBodySize {
+		c.Error(http.StatusRequestEntityTooLarge, bodyTooLarge(protocol.maxBodySize))
+		c.Abort()
+		return
+	}
+
+	c.Next()
+}
+
+func (r *Router) bodyLimit(route *Route) int64 {
+	if route.maxBodySize != 0 {
+		return route.maxBodySize
+	}
+
+	return r.maxBodySize
+}
+
+// A NotFound or MethodNotAllowed handler wrapped with the global middleware, replacing the route of the slot
+func (r *Router) fallback(slot string, handler contract.ContextHandler) http.Handler {
+	r.mustNotFrozen()
+	route := newRoute(r, ``, handler)
+	r.fallbacks[slot] = route
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		handlers := route.compiled
+		if handlers == nil {
+			handlers = r.handlers(``, route.Handlers()...)
+		}
+
+		protocol := NewHttp(req, w).(*Http)
+		protocol.SetPrettyJSON(r.prettyJSON)
+		kernel.NewContext(r.Firmeve, protocol, handlers...).Next()
 	})
+}
+
+func (r *Router) compile(route *Route) {
+	route.compiled = r.routeHandlers(route)
+	for _, child := range route.children {
+		r.compile(child)
+	}
+}
+
+// The handlers compiled by Freeze, or assembled for the request before the router is frozen
+func (r *Router) compiledHandlers(route *Route) []contract.ContextHandler {
+	if route.compiled != nil {
+		return route.compiled
+	}
+
+	return r.routeHandlers(route)
+}
+
+func (r *Router) mustNotFrozen() {
+	if r.frozen {
+		panic(fmt.Errorf("the router is frozen"))
+	}
+}
+
+// Respond with the NotFound handler of the router
+func (r *Router) notFound(c contract.Context) {
+	protocol := c.Protocol().(contract.HttpProtocol)
+	r.serveNotFound(protocol.ResponseWriter(), protocol.Request())
+}
 
-	return r.routes[key]
+func (r *Router) serveNotFound(w http.ResponseWriter, req *http.Request) {
+	if r.router.NotFound != nil {
+		r.router.NotFound.ServeHTTP(w, req)
+	} else {
+		http.NotFound(w, req)
+	}
+}
+
+// Check the parameters against the route and global patterns
+func (r *Router) matchPatterns(route *Route, params map[string]string) bool {
+	for param, value := range params {
+		pattern, ok := route.patterns[param]
+		if !ok {
+			pattern, ok = r.patterns[param]
+		}
+
+		if ok && !pattern.MatchString(value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// The full handlers of the route, global middleware, the body limit, model binding, middleware aliases, before handlers, handler and after handlers
+func (r *Router) routeHandlers(route *Route) []contract.ContextHandler {
+	handlers := []contract.ContextHandler{r.limitBody}
+	if len(r.models) > 0 {
+		handlers = append(handlers, r.bindModels)
+	}
+
+	return r.middlewareHandlers(route, handlers...)
+}
+
+// The handlers of the route after the given handlers, without model binding
+func (r *Router) middlewareHandlers(route *Route, handlers ...contract.ContextHandler) []contract.ContextHandler {
+	handlers = append(handlers, r.resolveMiddleware(route.middleware)...)
+
+	return r.handlers(route.name, append(handlers, route.Handlers()...)...)
+}
+
+// Wrap the handlers of the named route with the global middleware
+func (r *Router) handlers(name string, handlers ...contract.ContextHandler) []contract.ContextHandler {
+	newHandlers := make([]contract.ContextHandler, 0, len(handlers))
+	for _, middleware := range r.beforeMiddleware {
+		if !middleware.excepted(name) {
+			newHandlers = append(newHandlers, middleware.handlers...)
+		}
+	}
+
+	newHandlers = append(newHandlers, handlers...)
+
+	for _, middleware := range r.afterMiddleware {
+		if !middleware.excepted(name) {
+			newHandlers = append(newHandlers, middleware.handlers...)
+		}
+	}
+
+	return newHandlers
 }
 
 func (r *Router) routeKey(method, path string) string {
@@ -114,6 +387,12 @@ func (r *Router) routeKey(method, path string) string {
 // 其它router,middleware这些都是我自己实现，惟一对接的就是无缝的写入一套httprouter规则的路由（后期替换为自己的路由）
 // 通过ServerHttp去查找匹配路由
 func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if len(r.domainPatterns) > 0 {
+		if d, req := r.matchDomain(req); d != nil {
+			d.router.ServeHTTP(w, req)
+			return
+		}
+	}
 
 	r.router.ServeHTTP(w, req)
 
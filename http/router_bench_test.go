@@ -0,0 +1,81 @@
+package http
+
+import (
+	"github.com/firmeve/firmeve/kernel"
+	"github.com/firmeve/firmeve/kernel/contract"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newBenchmarkRouter() *Router {
+	router := newTestingRouter()
+	handler := func(ctx contract.Context) {
+		ctx.Next()
+	}
+	router.Use(handler, handler)
+	router.AliasMiddleware(`auth`, handler)
+	router.MiddlewareGroup(`api`, `auth`)
+	v1 := router.Group(`/api/v1`).Middleware(`api`).Before(handler)
+	for _, path := range []string{`/users`, `/users/:id`, `/posts`, `/posts/:id`, `/comments/:id`} {
+		v1.GET(path, handler).Before(handler).After(handler)
+	}
+
+	return router
+}
+
+// The dispatch before Router.Freeze, looking up the route and assembling its handlers per request
+func newBaselineBenchmarkRouter() http.Handler {
+	router := newBenchmarkRouter()
+	path := `/api/v1/posts/:id`
+	key := router.routeKey(http.MethodGet, path)
+	baseline := httprouter.New()
+	baseline.GET(path, func(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+		route, params := router.routes[key].child(params)
+		ctxParams := make(map[string]string, 0)
+		for _, param := range append(hostParams(req), params...) {
+			ctxParams[param.Key] = param.Value
+		}
+
+		if !router.matchPatterns(route, ctxParams) {
+			router.serveNotFound(w, req)
+			return
+		}
+
+		protocol := NewHttp(req, w).(*Http)
+		protocol.SetParams(ctxParams)
+		ctx := kernel.NewContext(router.Firmeve, protocol, router.routeHandlers(route)...)
+		router.Firmeve.Get(`event`).(contract.Event).Dispatch(`router.match`, map[string]interface{}{
+			`context`: ctx,
+			`route`:   route,
+		})
+
+		ctx.Next()
+	})
+
+	return baseline
+}
+
+func benchmarkDispatch(b *testing.B, router http.Handler) {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, `/api/v1/posts/1`, nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		router.ServeHTTP(w, req)
+	}
+}
+
+func BenchmarkRouter_Dispatch_Baseline(b *testing.B) {
+	benchmarkDispatch(b, newBaselineBenchmarkRouter())
+}
+
+func BenchmarkRouter_Dispatch(b *testing.B) {
+	benchmarkDispatch(b, newBenchmarkRouter())
+}
+
+func BenchmarkRouter_Dispatch_Frozen(b *testing.B) {
+	benchmarkDispatch(b, newBenchmarkRouter().Freeze())
+}
@@ -1,9 +1,15 @@
 package http
 
 import (
+	"errors"
+	"github.com/firmeve/firmeve/event"
+	"github.com/firmeve/firmeve/kernel/contract"
+	render2 "github.com/firmeve/firmeve/render"
 	testing2 "github.com/firmeve/firmeve/testing"
 	"github.com/julienschmidt/httprouter"
 	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/mock"
@@ -44,48 +50,48 @@ func assertBaseRoute(t *testing.T, router *Router, method, path, name string, be
 
 func TestRouter_BaseRoute(t *testing.T) {
 	router := New(testing2.TestingModeFirmeve())
-	router.GET("/gets/1", func(ctx *Context) {
-		ctx.Write([]byte("Body"))
+	router.GET("/gets/1", func(ctx contract.Context) {
+		ctx.Protocol().Write([]byte("Body"))
 		ctx.Next()
-	}).After(func(ctx *Context) {
-		ctx.Write([]byte("After 1"))
+	}).After(func(ctx contract.Context) {
+		ctx.Protocol().Write([]byte("After 1"))
 		ctx.Next()
-	}).After(func(ctx *Context) {
-		ctx.Write([]byte("After 2"))
+	}).After(func(ctx contract.Context) {
+		ctx.Protocol().Write([]byte("After 2"))
 		ctx.Next()
-	}).Before(func(ctx *Context) {
-		ctx.Write([]byte("Before 1"))
+	}).Before(func(ctx contract.Context) {
+		ctx.Protocol().Write([]byte("Before 1"))
 		ctx.Next()
 	}).Name("gets.1")
 
 	assertBaseRoute(t, router, http.MethodGet, "/gets/1", "gets.1", 1, 2)
 
-	router.POST("/posts", func(ctx *Context) {
-		ctx.Write([]byte("Body"))
+	router.POST("/posts", func(ctx contract.Context) {
+		ctx.Protocol().Write([]byte("Body"))
 		ctx.Next()
 	}).Name("posts.1")
 	assertBaseRoute(t, router, http.MethodPost, "/posts", "posts.1", 0, 0)
 
-	router.PUT("/resources/1/put", func(ctx *Context) {
-		ctx.Write([]byte("Body"))
+	router.PUT("/resources/1/put", func(ctx contract.Context) {
+		ctx.Protocol().Write([]byte("Body"))
 		ctx.Next()
 	})
 	assertBaseRoute(t, router, http.MethodPut, "/resources/1/put", "", 0, 0)
 
-	router.DELETE("/1/delete", func(ctx *Context) {
-		ctx.Write([]byte("Body"))
+	router.DELETE("/1/delete", func(ctx contract.Context) {
+		ctx.Protocol().Write([]byte("Body"))
 		ctx.Next()
 	})
 	assertBaseRoute(t, router, http.MethodDelete, "/1/delete", "", 0, 0)
 
-	router.PATCH("/patch", func(ctx *Context) {
-		ctx.Write([]byte("Body"))
+	router.PATCH("/patch", func(ctx contract.Context) {
+		ctx.Protocol().Write([]byte("Body"))
 		ctx.Next()
 	}).Name("patch")
 	assertBaseRoute(t, router, http.MethodPatch, "/patch", "patch", 0, 0)
 
-	router.OPTIONS("/options", func(ctx *Context) {
-		ctx.Write([]byte("Body"))
+	router.OPTIONS("/options", func(ctx contract.Context) {
+		ctx.Protocol().Write([]byte("Body"))
 		ctx.Next()
 	})
 	assertBaseRoute(t, router, http.MethodOptions, "/options", "", 0, 0)
@@ -105,49 +111,49 @@ func TestRouter_HttpRouter(t *testing.T) {
 
 func TestRouter_Group(t *testing.T) {
 	router := New(testing2.TestingModeFirmeve())
-	v1 := router.Group("/v1").After(func(ctx *Context) {
-		ctx.Write([]byte("Group v1 After"))
+	v1 := router.Group("/v1").After(func(ctx contract.Context) {
+		ctx.Protocol().Write([]byte("Group v1 After"))
 		ctx.Next()
-	}).Before(Recovery, func(ctx *Context) {
-		ctx.Write([]byte("Group v1 Before"))
+	}).Before(Recovery, func(ctx contract.Context) {
+		ctx.Protocol().Write([]byte("Group v1 Before"))
 		ctx.Next()
 	})
 	{
-		v1.GET("/gets/1", func(ctx *Context) {
-			ctx.Write([]byte("bdc"))
+		v1.GET("/gets/1", func(ctx contract.Context) {
+			ctx.Protocol().Write([]byte("bdc"))
 			ctx.Next()
 		}).Name("gets.1")
 		assertBaseRoute(t, router, http.MethodGet, "/v1/gets/1", "gets.1", 2, 1)
 
-		v1.POST("/posts", func(ctx *Context) {
+		v1.POST("/posts", func(ctx contract.Context) {
 			ctx.Next()
 		}).Name("v1.posts")
 		assertBaseRoute(t, router, http.MethodPost, "/v1/posts", "v1.posts", 2, 1)
 
 		//
-		v1.DELETE("/delete", func(ctx *Context) {
+		v1.DELETE("/delete", func(ctx contract.Context) {
 		})
 		assertBaseRoute(t, router, http.MethodDelete, "/v1/delete", "", 2, 1)
 
-		v1.PUT("/put", func(ctx *Context) {
+		v1.PUT("/put", func(ctx contract.Context) {
 		})
 		assertBaseRoute(t, router, http.MethodPut, "/v1/put", "", 2, 1)
 
-		v1.PATCH("/patch", func(ctx *Context) {
+		v1.PATCH("/patch", func(ctx contract.Context) {
 		})
 		assertBaseRoute(t, router, http.MethodPatch, "/v1/patch", "", 2, 1)
 
-		v1.OPTIONS("/options", func(ctx *Context) {
+		v1.OPTIONS("/options", func(ctx contract.Context) {
 		})
 		assertBaseRoute(t, router, http.MethodOptions, "/v1/options", "", 2, 1)
 	}
 
-	v1Dep := v1.Group("/dep").Before(func(ctx *Context) {
-		ctx.Write([]byte("Group v1--dep before"))
+	v1Dep := v1.Group("/dep").Before(func(ctx contract.Context) {
+		ctx.Protocol().Write([]byte("Group v1--dep before"))
 		ctx.Next()
 	})
 	{
-		v1Dep.GET("/gets/1", func(ctx *Context) {
+		v1Dep.GET("/gets/1", func(ctx contract.Context) {
 
 		})
 	}
@@ -161,29 +167,29 @@ func TestRouter_Group(t *testing.T) {
 //	f.Bind(`event`, event.New())
 //	router := New(f)
 //	router.Static("/file", "/tmp")
-//	router.GET("/gets/:name", func(ctx *Context) {
-//		ctx.Write([]byte(ctx.Param("name")))
+//	router.GET("/gets/:name", func(ctx contract.Context) {
+//		ctx.Protocol().Write([]byte(ctx.Param("name")))
 //		ctx.Next()
 //	})
-//	router.NotFound(func(ctx *Context) {
-//		ctx.Write([]byte("zzzz"))
+//	router.NotFound(func(ctx contract.Context) {
+//		ctx.Protocol().Write([]byte("zzzz"))
 //		ctx.Next()
 //	})
 //	req, _ := http.NewRequest(http.MethodGet, "/gets/abc", nil)
 //	router.ServeHTTP(&MockResponseWriter{}, req)
 //	req2, _ := http.NewRequest(http.MethodGet, "/ssssss", nil)
 //	router.ServeHTTP(&MockResponseWriter{}, req2)
-//	//router.GET("/gets/1", func(ctx *Context) {
-//	//	ctx.Write([]byte("Body"))
+//	//router.GET("/gets/1", func(ctx contract.Context) {
+//	//	ctx.Protocol().Write([]byte("Body"))
 //	//	ctx.Next()
-//	//}).After(func(ctx *Context) {
-//	//	ctx.Write([]byte("After 1"))
+//	//}).After(func(ctx contract.Context) {
+//	//	ctx.Protocol().Write([]byte("After 1"))
 //	//	ctx.Next()
-//	//}).After(func(ctx *Context) {
-//	//	ctx.Write([]byte("After 2"))
+//	//}).After(func(ctx contract.Context) {
+//	//	ctx.Protocol().Write([]byte("After 2"))
 //	//	ctx.Next()
-//	//}).Before(func(ctx *Context) {
-//	//	ctx.Write([]byte("Before 1"))
+//	//}).Before(func(ctx contract.Context) {
+//	//	ctx.Protocol().Write([]byte("Before 1"))
 //	//	ctx.Next()
 //	//}).Name("gets.1")
 //	//err := http.ListenAndServe("127.0.0.1:28084", router)
@@ -191,3 +197,180 @@ func TestRouter_Group(t *testing.T) {
 //	//	fmt.Println(err)
 //	//}
 //}
+
+func newTestingRouter() *Router {
+	f := testing2.TestingModeFirmeve()
+	f.Bind(`event`, event.New())
+	return New(f)
+}
+
+func serveTestingRequest(router *Router, method, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+	return w
+}
+
+func TestRouter_MethodRoutes(t *testing.T) {
+	router := newTestingRouter()
+	handler := func(ctx contract.Context) {
+		ctx.Protocol().Write([]byte(ctx.Protocol().(contract.HttpProtocol).Request().Method))
+		ctx.Next()
+	}
+
+	router.HEAD("/head", handler)
+	assertBaseRoute(t, router, http.MethodHead, "/head", "", 0, 0)
+
+	anyRoute := router.Any("/any", handler).Name("any")
+	for _, method := range anyMethods {
+		assert.Same(t, anyRoute, router.routes[router.routeKey(method, "/any")])
+	}
+	assert.Equal(t, http.MethodPatch, serveTestingRequest(router, http.MethodPatch, "/any").Body.String())
+	assert.Nil(t, router.routes[router.routeKey(http.MethodTrace, "/any")])
+	assert.Nil(t, router.routes[router.routeKey(http.MethodConnect, "/any")])
+	assert.Equal(t, http.StatusMethodNotAllowed, serveTestingRequest(router, http.MethodTrace, "/any").Code)
+
+	matchRoute := router.Match([]string{http.MethodGet, http.MethodPost}, "/match", handler)
+	assert.Same(t, matchRoute, router.routes[router.routeKey(http.MethodGet, "/match")])
+	assert.Same(t, matchRoute, router.routes[router.routeKey(http.MethodPost, "/match")])
+	assert.Nil(t, router.routes[router.routeKey(http.MethodPut, "/match")])
+
+	v1 := router.Group("/v1").Before(handler)
+	v1.HEAD("/head", handler)
+	assertBaseRoute(t, router, http.MethodHead, "/v1/head", "", 1, 0)
+	v1.Any("/any", handler)
+	assertBaseRoute(t, router, http.MethodDelete, "/v1/any", "", 1, 0)
+	v1.Match([]string{http.MethodPut}, "/match", handler)
+	assertBaseRoute(t, router, http.MethodPut, "/v1/match", "", 1, 0)
+}
+
+func TestRouter_MethodNotAllowed(t *testing.T) {
+	router := newTestingRouter()
+	router.Match([]string{http.MethodGet, http.MethodPost}, "/match", func(ctx contract.Context) {
+		ctx.Next()
+	})
+	router.MethodNotAllowed(func(ctx contract.Context) {
+		ctx.RenderWith(http.StatusMethodNotAllowed, render2.Plain, []byte("not allowed"))
+		ctx.Next()
+	})
+
+	w := serveTestingRequest(router, http.MethodDelete, "/match")
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "not allowed", w.Body.String())
+	assert.Contains(t, w.Header().Get(`Allow`), http.MethodGet)
+	assert.Contains(t, w.Header().Get(`Allow`), http.MethodPost)
+}
+
+func TestRouter_Where(t *testing.T) {
+	router := newTestingRouter()
+	router.Pattern(`id`, `[0-9]+`)
+	router.NotFound(writeHandler(`not found`))
+	router.GET(`/users/:id`, func(ctx contract.Context) {
+		id, err := ctx.ParamInt(`id`)
+		assert.Nil(t, err)
+		ctx.Protocol().Write([]byte(strconv.Itoa(id + 1)))
+		ctx.Next()
+	})
+	router.GET(`/posts/:id`, writeHandler(`post`)).Where(`id`, `[a-z]+`)
+	router.GET(`/orders/:uuid`, func(ctx contract.Context) {
+		id, err := ctx.ParamUUID(`uuid`)
+		assert.Nil(t, err)
+		ctx.Protocol().Write([]byte(id.String()))
+		ctx.Next()
+	}).Where(`uuid`, `[0-9a-f-]{36}`)
+
+	assert.Equal(t, `11`, serveTestingRequest(router, http.MethodGet, `/users/10`).Body.String())
+	assert.Equal(t, `not found`, serveTestingRequest(router, http.MethodGet, `/users/abc`).Body.String())
+	assert.Equal(t, `post`, serveTestingRequest(router, http.MethodGet, `/posts/abc`).Body.String())
+	assert.Equal(t, `not found`, serveTestingRequest(router, http.MethodGet, `/posts/10`).Body.String())
+	assert.Equal(t, `6ba7b810-9dad-11d1-80b4-00c04fd430c8`, serveTestingRequest(router, http.MethodGet, `/orders/6ba7b810-9dad-11d1-80b4-00c04fd430c8`).Body.String())
+	assert.Equal(t, `not found`, serveTestingRequest(router, http.MethodGet, `/orders/1`).Body.String())
+}
+
+func TestRouter_Freeze(t *testing.T) {
+	router := newTestingRouter()
+	global := router.Use(writeHandler(`global-`))
+	router.AliasMiddleware(`auth`, writeHandler(`auth-`))
+	router.NotFound(writeHandler(`not found`))
+	route := router.GET(`/users/:id`, writeHandler(`user`)).Middleware(`auth`).Before(writeHandler(`before-`))
+	resource := router.Resource(`/comments`, new(mockCommentController))
+
+	assert.False(t, router.IsFrozen())
+	assert.Same(t, router, router.Freeze())
+	assert.True(t, router.IsFrozen())
+	assert.Same(t, router, router.Freeze())
+
+	assert.Equal(t, `global-auth-before-user`, serveTestingRequest(router, http.MethodGet, `/users/1`).Body.String())
+	assert.Equal(t, `global-not found`, serveTestingRequest(router, http.MethodGet, `/missing`).Body.String())
+	assert.Equal(t, `global-comment.create:`, serveTestingRequest(router, http.MethodGet, `/comments/create`).Body.String())
+
+	assert.Panics(t, func() {
+		router.GET(`/late`, writeHandler(`late`))
+	})
+	assert.Panics(t, func() {
+		router.Use(writeHandler(`late`))
+	})
+	assert.Panics(t, func() {
+		router.AliasMiddleware(`late`, writeHandler(`late`))
+	})
+	assert.Panics(t, func() {
+		router.Group(`/admin`).POST(`/late`, writeHandler(`late`))
+	})
+	assert.Panics(t, func() {
+		router.NotFound(writeHandler(`late`))
+	})
+
+	// the compiled handlers of the routes no longer change
+	for _, f := range []func(){
+		func() { route.Before(writeHandler(`late`)) },
+		func() { route.After(writeHandler(`late`)) },
+		func() { route.Where(`id`, `[0-9]+`) },
+		func() { route.Middleware(`auth`) },
+		func() { route.MaxBodySize(1024) },
+		func() { route.Name(`users.show`) },
+		func() { resource.Before(writeHandler(`late`)) },
+		func() { global.Except(`users.show`) },
+	} {
+		assert.Panics(t, f)
+	}
+	assert.Equal(t, `global-auth-before-user`, serveTestingRequest(router, http.MethodGet, `/users/1`).Body.String())
+}
+
+func TestRouter_SetPrettyJSON(t *testing.T) {
+	router := newTestingRouter()
+	router.GET(`/users`, func(c contract.Context) {
+		c.Render(http.StatusOK, []int{1})
+		c.Next()
+	})
+	assert.Equal(t, `[1]`, serveTestingRequest(router, http.MethodGet, `/users`).Body.String())
+
+	router.SetPrettyJSON(true)
+	assert.Equal(t, "[\n  1\n]", serveTestingRequest(router, http.MethodGet, `/users`).Body.String())
+}
+
+func TestRouter_Error_XML(t *testing.T) {
+	router := newTestingRouter()
+	router.GET(`/users`, func(c contract.Context) {
+		c.Error(http.StatusBadRequest, errors.New(`bad request`))
+	})
+
+	// the error map can not be encoded as xml
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, `/users`, nil)
+	req.Header.Set(`Accept`, contract.HttpMimeXml)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, `application/json`, w.Header().Get(`Content-Type`))
+	assert.Contains(t, w.Body.String(), `bad request`)
+}
+
+func TestRouter_NotFound_Replace(t *testing.T) {
+	router := newTestingRouter()
+	router.NotFound(writeHandler(`first`))
+	router.NotFound(writeHandler(`second`))
+	router.MethodNotAllowed(writeHandler(`not allowed`))
+	router.StaticFS(`/`, http.Dir(`.`))
+	assert.Equal(t, 2, len(router.fallbacks))
+
+	router.Freeze()
+	assert.Equal(t, `second`, serveTestingRequest(router, http.MethodGet, `/missing`).Body.String())
+}
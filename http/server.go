@@ -4,10 +4,11 @@ import (
 	"context"
 	"fmt"
 	kernel2 "github.com/firmeve/firmeve/bootstrap"
+	"github.com/firmeve/firmeve/config"
 	"github.com/firmeve/firmeve/kernel"
 	"github.com/firmeve/firmeve/kernel/contract"
 	"github.com/spf13/cobra"
-	"golang.org/x/net/http2"
+	"net"
 	net_http "net/http"
 	"os"
 	"os/signal"
@@ -32,13 +33,15 @@ func (c *HttpCommand) newCmd() *cobra.Command {
 	c.command = new(cobra.Command)
 	c.command.Use = "http:serve"
 	c.command.Short = "Http server"
-	c.command.Flags().StringP("host", "H", ":80", "Http serve address")
+	c.command.Flags().StringP("host", "H", "", "Http serve address, overriding the server config http.host")
+	c.command.Flags().StringSliceP("listen", "", nil, "Extra listen addresses, e.g. unix:///tmp/firmeve.sock or tcp://:8081")
+	c.command.Flags().StringP("socket-mode", "", "", "Unix domain socket file permissions, overriding the server config http.socket_mode")
 	c.command.Flags().BoolP("http2", "", false, "Open http2 protocol")
+	c.command.Flags().BoolP("h2c", "", false, "Open cleartext http2 protocol")
 	c.command.Flags().StringP("cert-file", "", "", "Http2 cert file path")
 	c.command.Flags().StringP("key-file", "", "", "Http2 key file path")
-
+	c.command.Flags().DurationP("shutdown-timeout", "", 15*time.Second, "Graceful shutdown timeout")
 	c.command.Run = c.run
-
 	return c.command
 }
 
@@ -46,53 +49,50 @@ func (c *HttpCommand) run(cmd *cobra.Command, args []string) {
 	// bootstrap
 	kernel2.BootFromCommand(c)
 
-	//config := c.Firmeve.Get(`config`).(*config.Config)
 	logger := c.Firmeve.Get(`logger`).(contract.Loggable)
-	var (
-		host      = cmd.Flag("host").Value.String()
-		certFile  = cmd.Flag(`cert-file`).Value.String()
-		keyFile   = cmd.Flag(`key-file`).Value.String()
-		openHttp2 = cmd.Flag(`http2`).Value.String()
-	)
-	srv := &net_http.Server{
-		Addr:    host,
-		Handler: c.Firmeve.Get(`http.router`).(*Router),
+	serverConfig := NewServerConfig(c.Firmeve.Get(`config`).(*config.Config).Item(`server`)).MergeFlags(cmd)
+
+	// Compile the route handlers once, the routes are all registered by the providers at boot
+	router := serverConfig.ApplyLimits(c.Firmeve.Get(`http.router`).(*Router))
+	srv, err := serverConfig.Server(router.Freeze())
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("server: %s\n", err))
 	}
 
-	go func() {
-		var err error
+	event := c.Firmeve.Get(`event`).(contract.Event)
+	event.Dispatch(`server.starting`, map[string]interface{}{
+		`server`: srv,
+		`config`: serverConfig,
+	})
 
-		// ssl
-		if certFile != `` && keyFile != `` {
+	listeners, err := serverConfig.Listeners()
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("listen: %s\n", err))
+	}
 
-			err = srv.ListenAndServeTLS(certFile, keyFile)
+	for i := range listeners {
+		go serve(srv, serverConfig, listeners[i], logger)
+	}
 
-			// http2
-			if openHttp2 == `true` {
-				//@todo conf is empty
-				err = http2.ConfigureServer(srv, &http2.Server{})
-			}
-		} else {
-			err = srv.ListenAndServe()
-		}
+	event.Dispatch(`server.started`, map[string]interface{}{
+		`server`:    srv,
+		`config`:    serverConfig,
+		`listeners`: listeners,
+	})
+	// Report to the parent process when started by a graceful restart
+	if err := NotifyReady(); err != nil {
+		logger.Error(fmt.Sprintf("notify ready: %s", err))
+	}
 
-		if err != nil && err != net_http.ErrServerClosed {
-			logger.Fatal(fmt.Sprintf("listen: %s\n", err))
-		}
-	}()
+	restart := c.wait(listeners, serverConfig, logger)
 
-	// Wait for interrupt signal to gracefully shutdown the server with
-	// a timeout of 5 seconds.
-	quit := make(chan os.Signal, 1)
-	// kill (no param) default send syscall.SIGTERM
-	// kill -2 is syscall.SIGINT
-	// kill -9 is syscall.SIGKILL but can't be catch, so don't need add it
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-	//log.Println("Shutdown Server ...")
 	logger.Info("Shutdown Server ...")
-
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	event.Dispatch(`server.stopping`, map[string]interface{}{
+		`server`:  srv,
+		`config`:  serverConfig,
+		`restart`: restart,
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), serverConfig.ShutdownTimeout)
 	defer cancel()
 	if err := srv.Shutdown(ctx); err != nil {
 		logger.Fatal("Server Shutdown: ", err)
@@ -100,3 +100,47 @@ func (c *HttpCommand) run(cmd *cobra.Command, args []string) {
 
 	logger.Info("Server exiting")
 }
+
+// Block until the server should stop, returns true when a new process took over the listeners
+func (c *HttpCommand) wait(listeners []net.Listener, serverConfig *ServerConfig, logger contract.Loggable) bool {
+	quit := make(chan os.Signal, 1)
+	// kill (no param) default send syscall.SIGTERM
+	// kill -2 is syscall.SIGINT
+	// kill -1 is syscall.SIGHUP, graceful restart
+	// kill -9 is syscall.SIGKILL but can't be catch, so don't need add it
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(quit)
+
+	for sig := range quit {
+		if sig != syscall.SIGHUP {
+			return false
+		}
+
+		logger.Info("Restart Server ...")
+		process, err := Restart(listeners, serverConfig.RestartTimeout)
+		if err != nil {
+			logger.Error(fmt.Sprintf("restart: %s", err))
+			continue
+		}
+
+		logger.Info(fmt.Sprintf("Server restarted with pid %d", process.Pid))
+		return true
+	}
+
+	return false
+}
+
+func serve(srv *net_http.Server, serverConfig *ServerConfig, listener net.Listener, logger contract.Loggable) {
+	var err error
+	logger.Info(fmt.Sprintf("Listening on %s://%s", listener.Addr().Network(), listener.Addr().String()))
+	// ssl
+	if serverConfig.IsTLS() {
+		err = srv.ServeTLS(listener, serverConfig.CertFile, serverConfig.KeyFile)
+	} else {
+		err = srv.Serve(listener)
+	}
+
+	if err != nil && err != net_http.ErrServerClosed {
+		logger.Fatal(fmt.Sprintf("serve: %s\n", err))
+	}
+}
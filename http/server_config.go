@@ -0,0 +1,217 @@
+package http
+
+import (
+	"crypto/tls"
+	"fmt"
+	"github.com/firmeve/firmeve/kernel/contract"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"net"
+	net_http "net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+type (
+	ServerConfig struct {
+		Host              string
+		Listen            []string
+		SocketMode        string
+		ReadTimeout       time.Duration
+		ReadHeaderTimeout time.Duration
+		WriteTimeout      time.Duration
+		IdleTimeout       time.Duration
+		MaxHeaderBytes    int
+		CertFile          string
+		KeyFile           string
+		MinTLSVersion     string
+		HTTP2             bool
+		H2C               bool
+		ShutdownTimeout   time.Duration
+		RestartTimeout    time.Duration
+		// The memory of parsing the multipart forms, 0 keeps the memory set on the router
+		MaxMultipartMemory int64
+		// The max size of the request bodies, 0 keeps the limit set on the router and a negative size means no limit
+		MaxBodySize int64
+	}
+)
+
+var (
+	tlsVersions = map[string]uint16{
+		`1.0`: tls.VersionTLS10,
+		`1.1`: tls.VersionTLS11,
+		`1.2`: tls.VersionTLS12,
+		`1.3`: tls.VersionTLS13,
+	}
+)
+
+// Create a server config from the `http` node of the server config item
+func NewServerConfig(config contract.Configuration) *ServerConfig {
+	config.SetDefault(`http.host`, `:80`)
+	config.SetDefault(`http.socket_mode`, `0660`)
+	config.SetDefault(`http.max_header_bytes`, net_http.DefaultMaxHeaderBytes)
+	config.SetDefault(`http.tls.min_version`, `1.2`)
+	config.SetDefault(`http.shutdown_timeout`, 15*time.Second)
+	config.SetDefault(`http.restart_timeout`, 30*time.Second)
+
+	return &ServerConfig{
+		Host:               config.GetString(`http.host`),
+		Listen:             config.GetStringSlice(`http.listen`),
+		SocketMode:         config.GetString(`http.socket_mode`),
+		ReadTimeout:        config.GetDuration(`http.read_timeout`),
+		ReadHeaderTimeout:  config.GetDuration(`http.read_header_timeout`),
+		WriteTimeout:       config.GetDuration(`http.write_timeout`),
+		IdleTimeout:        config.GetDuration(`http.idle_timeout`),
+		MaxHeaderBytes:     config.GetInt(`http.max_header_bytes`),
+		CertFile:           config.GetString(`http.tls.cert_file`),
+		KeyFile:            config.GetString(`http.tls.key_file`),
+		MinTLSVersion:      config.GetString(`http.tls.min_version`),
+		HTTP2:              config.GetBool(`http.http2`),
+		H2C:                config.GetBool(`http.h2c`),
+		ShutdownTimeout:    config.GetDuration(`http.shutdown_timeout`),
+		RestartTimeout:     config.GetDuration(`http.restart_timeout`),
+		MaxMultipartMemory: int64(config.GetInt(`http.max_multipart_memory`)),
+		MaxBodySize:        int64(config.GetInt(`http.max_body_size`)),
+	}
+}
+
+// Override the config with the flags explicitly passed on the command line
+func (s *ServerConfig) MergeFlags(cmd *cobra.Command) *ServerConfig {
+	flags := cmd.Flags()
+	if flags.Changed(`host`) {
+		s.Host, _ = flags.GetString(`host`)
+	}
+	if flags.Changed(`listen`) {
+		s.Listen, _ = flags.GetStringSlice(`listen`)
+	}
+	if flags.Changed(`socket-mode`) {
+		s.SocketMode, _ = flags.GetString(`socket-mode`)
+	}
+	if flags.Changed(`cert-file`) {
+		s.CertFile, _ = flags.GetString(`cert-file`)
+	}
+	if flags.Changed(`key-file`) {
+		s.KeyFile, _ = flags.GetString(`key-file`)
+	}
+	if flags.Changed(`http2`) {
+		s.HTTP2, _ = flags.GetBool(`http2`)
+	}
+	if flags.Changed(`h2c`) {
+		s.H2C, _ = flags.GetBool(`h2c`)
+	}
+	if flags.Changed(`shutdown-timeout`) {
+		s.ShutdownTimeout, _ = flags.GetDuration(`shutdown-timeout`)
+	}
+
+	return s
+}
+
+// Apply the configured limits to the router, the limits not configured keep the values set by the application
+func (s *ServerConfig) ApplyLimits(router *Router) *Router {
+	if s.MaxMultipartMemory > 0 {
+		router.SetMaxMultipartMemory(s.MaxMultipartMemory)
+	}
+	if s.MaxBodySize != 0 {
+		router.SetMaxBodySize(s.MaxBodySize)
+	}
+
+	return router
+}
+
+func (s *ServerConfig) IsTLS() bool {
+	return s.CertFile != `` && s.KeyFile != ``
+}
+
+// All addresses to listen on, the host first followed by the extra listen addresses
+func (s *ServerConfig) Addresses() []string {
+	addresses := make([]string, 0, len(s.Listen)+1)
+	if s.Host != `` {
+		addresses = append(addresses, s.Host)
+	}
+
+	return append(addresses, s.Listen...)
+}
+
+// Open the listeners of the server, listeners inherited by socket activation take precedence over the addresses
+func (s *ServerConfig) Listeners() ([]net.Listener, error) {
+	listeners, err := InheritedListeners()
+	if err != nil || len(listeners) > 0 {
+		return listeners, err
+	}
+
+	mode, err := parseFileMode(s.SocketMode)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := s.Addresses()
+	listeners = make([]net.Listener, 0, len(addresses))
+	for _, address := range addresses {
+		listener, err := Listen(address, mode)
+		if err != nil {
+			closeListeners(listeners)
+			return nil, err
+		}
+		listeners = append(listeners, listener)
+	}
+
+	return listeners, nil
+}
+
+// Build a configured net/http server, http2 is configured before the server starts listening
+func (s *ServerConfig) Server(handler net_http.Handler) (*net_http.Server, error) {
+	srv := &net_http.Server{
+		Addr:              s.Host,
+		ReadTimeout:       s.ReadTimeout,
+		ReadHeaderTimeout: s.ReadHeaderTimeout,
+		WriteTimeout:      s.WriteTimeout,
+		IdleTimeout:       s.IdleTimeout,
+		MaxHeaderBytes:    s.MaxHeaderBytes,
+	}
+
+	if s.IsTLS() {
+		minVersion, ok := tlsVersions[s.MinTLSVersion]
+		if !ok {
+			return nil, fmt.Errorf("unsupported tls version %s", s.MinTLSVersion)
+		}
+		srv.TLSConfig = &tls.Config{
+			MinVersion: minVersion,
+		}
+	}
+
+	h2Server := &http2.Server{
+		IdleTimeout: s.IdleTimeout,
+	}
+	// Cleartext http2 only makes sense without tls
+	if s.H2C && !s.IsTLS() {
+		handler = h2c.NewHandler(handler, h2Server)
+	}
+	srv.Handler = handler
+
+	if s.HTTP2 && s.IsTLS() {
+		if err := http2.ConfigureServer(srv, h2Server); err != nil {
+			return nil, err
+		}
+	} else if !s.HTTP2 && s.IsTLS() {
+		// A non-nil empty map disables the automatic http2 upgrade of net/http
+		srv.TLSNextProto = make(map[string]func(*net_http.Server, *tls.Conn, net_http.Handler), 0)
+	}
+
+	return srv, nil
+}
+
+// An empty mode keeps the permissions of the umask
+func parseFileMode(mode string) (os.FileMode, error) {
+	if mode == `` {
+		return 0, nil
+	}
+
+	v, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil || v == 0 || v > 0777 {
+		return 0, fmt.Errorf("invalid socket mode %s", mode)
+	}
+
+	return os.FileMode(v), nil
+}
@@ -0,0 +1,104 @@
+package http
+
+import (
+	"crypto/tls"
+	"github.com/firmeve/firmeve/config"
+	"github.com/firmeve/firmeve/support/path"
+	"github.com/stretchr/testify/assert"
+	net_http "net/http"
+	"os"
+	"testing"
+	"time"
+)
+
+func newTestingServerConfig() *ServerConfig {
+	return NewServerConfig(config.New(path.RunRelative(configPath)).Item(`server`))
+}
+
+func TestNewServerConfig(t *testing.T) {
+	serverConfig := newTestingServerConfig()
+	assert.Equal(t, `0.0.0.0:28088`, serverConfig.Host)
+	assert.Equal(t, 30*time.Second, serverConfig.ReadTimeout)
+	assert.Equal(t, 10*time.Second, serverConfig.ReadHeaderTimeout)
+	assert.Equal(t, 120*time.Second, serverConfig.IdleTimeout)
+	assert.Equal(t, 1048576, serverConfig.MaxHeaderBytes)
+	assert.Equal(t, int64(33554432), serverConfig.MaxMultipartMemory)
+	assert.Equal(t, int64(10485760), serverConfig.MaxBodySize)
+	assert.Equal(t, 15*time.Second, serverConfig.ShutdownTimeout)
+	assert.Equal(t, false, serverConfig.IsTLS())
+}
+
+func TestServerConfig_MergeFlags(t *testing.T) {
+	cmd := new(HttpCommand).Cmd()
+	assert.Nil(t, cmd.Flags().Parse([]string{`--host`, `:8080`, `--shutdown-timeout`, `3s`, `--h2c`}))
+
+	serverConfig := newTestingServerConfig().MergeFlags(cmd)
+	assert.Equal(t, `:8080`, serverConfig.Host)
+	assert.Equal(t, 3*time.Second, serverConfig.ShutdownTimeout)
+	assert.Equal(t, true, serverConfig.H2C)
+	// not passed flags keep the config value
+	assert.Equal(t, 30*time.Second, serverConfig.ReadTimeout)
+	assert.Equal(t, `0660`, serverConfig.SocketMode)
+
+	cmd = new(HttpCommand).Cmd()
+	assert.Nil(t, cmd.Flags().Parse([]string{`--socket-mode`, `0600`}))
+	serverConfig = newTestingServerConfig().MergeFlags(cmd)
+	assert.Equal(t, `0.0.0.0:28088`, serverConfig.Host)
+	assert.Equal(t, `0600`, serverConfig.SocketMode)
+}
+
+func TestServerConfig_Listeners_SocketMode(t *testing.T) {
+	for _, mode := range []string{`rw`, `0`, `01777`, `9`} {
+		serverConfig := newTestingServerConfig()
+		serverConfig.Host = `127.0.0.1:0`
+		serverConfig.SocketMode = mode
+		_, err := serverConfig.Listeners()
+		assert.EqualError(t, err, `invalid socket mode `+mode)
+	}
+
+	mode, err := parseFileMode(`0660`)
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0660), mode)
+}
+
+func TestServerConfig_Server(t *testing.T) {
+	serverConfig := newTestingServerConfig()
+	handler := net_http.NotFoundHandler()
+
+	srv, err := serverConfig.Server(handler)
+	assert.Nil(t, err)
+	assert.Equal(t, serverConfig.Host, srv.Addr)
+	assert.Equal(t, serverConfig.WriteTimeout, srv.WriteTimeout)
+	assert.Nil(t, srv.TLSConfig)
+
+	serverConfig.CertFile = `cert.pem`
+	serverConfig.KeyFile = `key.pem`
+	serverConfig.HTTP2 = true
+	srv, err = serverConfig.Server(handler)
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS12), srv.TLSConfig.MinVersion)
+	assert.Contains(t, srv.TLSConfig.NextProtos, `h2`)
+
+	serverConfig.MinTLSVersion = `0.9`
+	_, err = serverConfig.Server(handler)
+	assert.NotNil(t, err)
+}
+
+func TestServerConfig_ApplyLimits(t *testing.T) {
+	router := newTestingRouter().SetMaxBodySize(1024).SetMaxMultipartMemory(2048)
+	serverConfig := newTestingServerConfig()
+	serverConfig.ApplyLimits(router)
+	assert.Equal(t, int64(10485760), router.maxBodySize)
+	assert.Equal(t, int64(33554432), router.maxMemory)
+
+	// the limits not configured keep the values of the router
+	router = newTestingRouter().SetMaxBodySize(1024).SetMaxMultipartMemory(2048)
+	serverConfig.MaxBodySize, serverConfig.MaxMultipartMemory = 0, 0
+	serverConfig.ApplyLimits(router)
+	assert.Equal(t, int64(1024), router.maxBodySize)
+	assert.Equal(t, int64(2048), router.maxMemory)
+
+	serverConfig.MaxBodySize = -1
+	serverConfig.ApplyLimits(router)
+	assert.Equal(t, int64(-1), router.maxBodySize)
+}
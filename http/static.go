@@ -0,0 +1,276 @@
+package http
+
+import (
+	"fmt"
+	"github.com/firmeve/firmeve/kernel/contract"
+	"github.com/firmeve/firmeve/support"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+type (
+	staticOption struct {
+		maxAge    time.Duration
+		immutable bool
+		index     string
+		listing   bool
+		spa       bool
+	}
+
+	staticHandler struct {
+		router     *Router
+		fileSystem http.FileSystem
+		fileServer http.Handler
+		option     *staticOption
+	}
+
+	// Precompressed siblings in order of preference
+	staticEncoding struct {
+		name      string
+		extension string
+	}
+)
+
+var (
+	// Hidden paths served nonetheless, such as /.well-known/security.txt
+	staticHiddenAllowed = []string{`.well-known`}
+
+	staticEncodings = []staticEncoding{
+		{name: `br`, extension: `.br`},
+		{name: `gzip`, extension: `.gz`},
+	}
+)
+
+// The Cache-Control max-age of the static files, files are revalidated with no-cache by default
+func StaticMaxAge(maxAge time.Duration) support.Option {
+	return func(object support.Object) {
+		object.(*staticOption).maxAge = maxAge
+	}
+}
+
+// Mark the static files immutable, for fingerprinted assets
+func StaticImmutable() support.Option {
+	return func(object support.Object) {
+		object.(*staticOption).immutable = true
+	}
+}
+
+// The index file of directories, the default is index.html
+func StaticIndex(index string) support.Option {
+	return func(object support.Object) {
+		object.(*staticOption).index = index
+	}
+}
+
+// List the directories without index file, directory listing is off by default
+func StaticListing() support.Option {
+	return func(object support.Object) {
+		object.(*staticOption).listing = true
+	}
+}
+
+// Serve the root index file for missing paths without extension, so that the client side router handles them
+func StaticSPA() support.Option {
+	return func(object support.Object) {
+		object.(*staticOption).spa = true
+	}
+}
+
+// Serve the static files of the root directory
+func (r *Router) Static(path string, root string, options ...support.Option) *Router {
+	return r.StaticFS(path, http.Dir(root), options...)
+}
+
+// Serve the static files of the file system, e.g. embedded assets, through the global middleware.
+// Files are served with ETag, Last-Modified and Cache-Control headers, and the precompressed `.br` or `.gz` sibling
+// is preferred when the client accepts it. Mounted at `/` the files are served by the NotFound handler,
+// since httprouter does not allow a catch-all route next to other routes, and the missing files fall through
+// to the handler set with Router.NotFound
+func (r *Router) StaticFS(path string, fileSystem http.FileSystem, options ...support.Option) *Router {
+	handler := newStaticHandler(r, fileSystem, options...)
+	if path == `` || path == `/` {
+		r.mustNotFrozen()
+		r.staticRoot = true
+		r.router.NotFound = r.fallback(`not_found`, handler.Handle)
+		return r
+	}
+
+	r.Match([]string{http.MethodGet, http.MethodHead}, strings.Join([]string{strings.TrimSuffix(path, `/`), `/*filepath`}, ``), handler.Handle)
+	return r
+}
+
+func (s *staticHandler) Handle(c contract.Context) {
+	protocol := c.Protocol().(contract.HttpProtocol)
+	req := protocol.Request()
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		s.notFound(c)
+		return
+	}
+
+	name := protocol.Param(`filepath`)
+	if name == `` {
+		name = req.URL.Path
+	}
+	name = path.Clean(`/` + name)
+
+	file, info, err := s.open(name)
+	if err == nil && info.IsDir() {
+		file.Close()
+		index := path.Join(name, s.option.index)
+		if file, info, err = s.open(index); err == nil {
+			name = index
+		} else if s.option.listing {
+			s.list(protocol, name)
+			c.Next()
+			return
+		}
+	}
+
+	if err != nil && s.option.spa && path.Ext(name) == `` {
+		name = `/` + s.option.index
+		file, info, err = s.open(name)
+	}
+
+	if err == nil && info.IsDir() {
+		file.Close()
+		err = os.ErrNotExist
+	}
+
+	if err != nil {
+		s.notFound(c)
+		return
+	}
+
+	s.serve(protocol, name, file, info)
+	c.Next()
+}
+
+func (s *staticHandler) serve(protocol contract.HttpProtocol, name string, file http.File, info os.FileInfo) {
+	w, req := protocol.ResponseWriter(), protocol.Request()
+	header := w.Header()
+
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if contentType != `` {
+		header.Set(`Content-Type`, contentType)
+	}
+	header.Set(`Cache-Control`, s.cacheControl(name))
+	header.Add(`Vary`, `Accept-Encoding`)
+
+	// The content type of precompressed files can not be sniffed, and ranges would be ranges of the compressed bytes
+	if contentType != `` && req.Header.Get(`Range`) == `` {
+		for _, encoding := range staticEncodings {
+			if !acceptsEncoding(req, encoding.name) {
+				continue
+			}
+
+			compressed, compressedInfo, err := s.open(name + encoding.extension)
+			if err != nil {
+				continue
+			} else if compressedInfo.IsDir() {
+				compressed.Close()
+				continue
+			}
+
+			file.Close()
+			file, info = compressed, compressedInfo
+			header.Set(`Content-Encoding`, encoding.name)
+			break
+		}
+	}
+	defer file.Close()
+
+	header.Set(`ETag`, fmt.Sprintf(`"%x-%x"`, info.ModTime().UnixNano(), info.Size()))
+	// ServeContent sets Last-Modified and answers conditional and range requests
+	http.ServeContent(w, req, name, info.ModTime(), file)
+}
+
+func (s *staticHandler) list(protocol contract.HttpProtocol, name string) {
+	req := protocol.Request()
+	req.URL.Path = name
+	if !strings.HasSuffix(req.URL.Path, `/`) {
+		req.URL.Path += `/`
+	}
+
+	s.fileServer.ServeHTTP(protocol.ResponseWriter(), req)
+}
+
+func (s *staticHandler) cacheControl(name string) string {
+	// The index file of a single page application must always be revalidated
+	if s.option.maxAge <= 0 || path.Base(name) == s.option.index {
+		return `no-cache`
+	}
+
+	cacheControl := fmt.Sprintf(`public, max-age=%d`, int(s.option.maxAge.Seconds()))
+	if s.option.immutable {
+		cacheControl += `, immutable`
+	}
+
+	return cacheControl
+}
+
+func (s *staticHandler) open(name string) (http.File, os.FileInfo, error) {
+	// Hidden files such as .env are never served
+	for _, segment := range strings.Split(name, `/`) {
+		if strings.HasPrefix(segment, `.`) && !inStrings(segment, staticHiddenAllowed) {
+			return nil, nil, os.ErrNotExist
+		}
+	}
+
+	file, err := s.fileSystem.Open(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+
+	return file, info, nil
+}
+
+// The NotFound handler of the router runs in the context, which has already passed the global middleware
+func (s *staticHandler) notFound(c contract.Context) {
+	if s.router.notFoundHandler != nil {
+		s.router.notFoundHandler(c)
+		return
+	}
+
+	protocol := c.Protocol().(contract.HttpProtocol)
+	http.NotFound(protocol.ResponseWriter(), protocol.Request())
+	c.Abort()
+}
+
+func acceptsEncoding(req *http.Request, encoding string) bool {
+	for _, value := range strings.Split(req.Header.Get(`Accept-Encoding`), `,`) {
+		value = strings.TrimSpace(value)
+		if i := strings.Index(value, `;`); i != -1 {
+			if strings.TrimSpace(value[i+1:]) == `q=0` {
+				continue
+			}
+			value = strings.TrimSpace(value[:i])
+		}
+
+		if value == encoding || value == `*` {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newStaticHandler(router *Router, fileSystem http.FileSystem, options ...support.Option) *staticHandler {
+	return &staticHandler{
+		router:     router,
+		fileSystem: fileSystem,
+		fileServer: http.FileServer(fileSystem),
+		option: support.ApplyOption(&staticOption{
+			index: `index.html`,
+		}, options...).(*staticOption),
+	}
+}
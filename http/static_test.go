@@ -0,0 +1,183 @@
+package http
+
+import (
+	"errors"
+	"github.com/firmeve/firmeve/kernel/contract"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestingStaticDir(t *testing.T) string {
+	dir, err := ioutil.TempDir(``, `firmeve`)
+	assert.Nil(t, err)
+	assert.Nil(t, os.MkdirAll(filepath.Join(dir, `js`), 0755))
+	assert.Nil(t, os.MkdirAll(filepath.Join(dir, `docs`), 0755))
+	assert.Nil(t, os.MkdirAll(filepath.Join(dir, `.well-known`), 0755))
+	for name, content := range map[string]string{
+		`index.html`:               `<html>index</html>`,
+		`js/app.js`:                `console.log(1)`,
+		`js/app.js.gz`:             `gzip`,
+		`js/app.js.br`:             `brotli`,
+		`docs/readme`:              `readme`,
+		`.env`:                     `secret`,
+		`.well-known/security.txt`: `contact`,
+		`style.css`:                `body{}`,
+		`style.css.gz`:             `gzip-css`,
+	} {
+		assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	return dir
+}
+
+func serveTestingStaticRequest(router *Router, path string, header map[string]string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for key, value := range header {
+		req.Header.Set(key, value)
+	}
+	router.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestRouter_Static(t *testing.T) {
+	dir := newTestingStaticDir(t)
+	defer os.RemoveAll(dir)
+
+	router := newTestingRouter()
+	router.Static(`/assets`, dir, StaticMaxAge(time.Hour), StaticImmutable())
+
+	w := serveTestingStaticRequest(router, `/assets/js/app.js`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `console.log(1)`, w.Body.String())
+	assert.Equal(t, `public, max-age=3600, immutable`, w.Header().Get(`Cache-Control`))
+	assert.Contains(t, w.Header().Get(`Content-Type`), `javascript`)
+	assert.NotEmpty(t, w.Header().Get(`Last-Modified`))
+	etag := w.Header().Get(`ETag`)
+	assert.NotEmpty(t, etag)
+
+	w = serveTestingStaticRequest(router, `/assets/js/app.js`, map[string]string{`If-None-Match`: etag})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+
+	w = serveTestingStaticRequest(router, `/assets/js/app.js`, map[string]string{`Accept-Encoding`: `gzip, br`})
+	assert.Equal(t, `brotli`, w.Body.String())
+	assert.Equal(t, `br`, w.Header().Get(`Content-Encoding`))
+	assert.Contains(t, w.Header().Get(`Content-Type`), `javascript`)
+	assert.Equal(t, `Accept-Encoding`, w.Header().Get(`Vary`))
+
+	w = serveTestingStaticRequest(router, `/assets/js/app.js`, map[string]string{`Accept-Encoding`: `gzip, br;q=0`})
+	assert.Equal(t, `gzip`, w.Body.String())
+	assert.Equal(t, `gzip`, w.Header().Get(`Content-Encoding`))
+
+	w = serveTestingStaticRequest(router, `/assets/js/app.js`, map[string]string{`Accept-Encoding`: `gzip`, `Range`: `bytes=0-6`})
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+	assert.Equal(t, `console`, w.Body.String())
+	assert.Empty(t, w.Header().Get(`Content-Encoding`))
+
+	w = serveTestingStaticRequest(router, `/assets/`, nil)
+	assert.Equal(t, `<html>index</html>`, w.Body.String())
+	assert.Equal(t, `no-cache`, w.Header().Get(`Cache-Control`))
+
+	assert.Equal(t, http.StatusNotFound, serveTestingStaticRequest(router, `/assets/docs`, nil).Code)
+	assert.Equal(t, http.StatusNotFound, serveTestingStaticRequest(router, `/assets/.env`, nil).Code)
+	assert.Equal(t, http.StatusNotFound, serveTestingStaticRequest(router, `/assets/missing.js`, nil).Code)
+	assert.Equal(t, http.StatusNotFound, serveTestingStaticRequest(router, `/assets/../../etc/passwd`, nil).Code)
+}
+
+func TestRouter_Static_Listing(t *testing.T) {
+	dir := newTestingStaticDir(t)
+	defer os.RemoveAll(dir)
+
+	router := newTestingRouter()
+	router.Static(`/assets`, dir, StaticListing())
+
+	w := serveTestingStaticRequest(router, `/assets/docs/`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `readme`)
+	assert.Equal(t, `no-cache`, serveTestingStaticRequest(router, `/assets/style.css`, nil).Header().Get(`Cache-Control`))
+}
+
+func TestRouter_StaticFS_SPA(t *testing.T) {
+	dir := newTestingStaticDir(t)
+	defer os.RemoveAll(dir)
+
+	router := newTestingRouter()
+	router.Use(func(ctx contract.Context) {
+		ctx.Protocol().(contract.HttpProtocol).ResponseWriter().Header().Set(`X-Global`, `1`)
+		ctx.Next()
+	})
+	router.GET(`/api/users`, writeHandler(`users`))
+	router.StaticFS(`/`, http.Dir(dir), StaticSPA())
+
+	assert.Equal(t, `users`, serveTestingStaticRequest(router, `/api/users`, nil).Body.String())
+
+	w := serveTestingStaticRequest(router, `/style.css`, nil)
+	assert.Equal(t, `body{}`, w.Body.String())
+	assert.Equal(t, `1`, w.Header().Get(`X-Global`))
+
+	w = serveTestingStaticRequest(router, `/users/1/profile`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `<html>index</html>`, w.Body.String())
+	assert.Equal(t, `1`, w.Header().Get(`X-Global`))
+
+	assert.Equal(t, http.StatusNotFound, serveTestingStaticRequest(router, `/missing.js`, nil).Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, `/users/1/profile`, nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRouter_Static_NotFound(t *testing.T) {
+	dir := newTestingStaticDir(t)
+	defer os.RemoveAll(dir)
+
+	notFound := func(c contract.Context) {
+		c.Error(http.StatusNotFound, errors.New(`custom not found`))
+	}
+
+	router := newTestingRouter()
+	router.Use(func(ctx contract.Context) {
+		ctx.Protocol().(contract.HttpProtocol).ResponseWriter().Header().Add(`X-Global`, `1`)
+		ctx.Next()
+	})
+	router.NotFound(notFound)
+	router.Static(`/assets`, dir)
+	w := serveTestingStaticRequest(router, `/assets/missing.js`, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, []string{`1`}, w.Header().Values(`X-Global`))
+	assert.Contains(t, w.Body.String(), `custom not found`)
+
+	// the files at the root fall through to the NotFound handler set before or after
+	for _, notFoundFirst := range []bool{true, false} {
+		router = newTestingRouter()
+		if notFoundFirst {
+			router.NotFound(notFound)
+		}
+		router.StaticFS(`/`, http.Dir(dir))
+		if !notFoundFirst {
+			router.NotFound(notFound)
+		}
+
+		assert.Equal(t, `body{}`, serveTestingStaticRequest(router, `/style.css`, nil).Body.String())
+		w = serveTestingStaticRequest(router, `/missing.js`, nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), `custom not found`)
+	}
+}
+
+func TestRouter_Static_Hidden(t *testing.T) {
+	dir := newTestingStaticDir(t)
+	defer os.RemoveAll(dir)
+
+	router := newTestingRouter()
+	router.Static(`/assets`, dir)
+	assert.Equal(t, http.StatusNotFound, serveTestingStaticRequest(router, `/assets/.env`, nil).Code)
+	assert.Equal(t, `contact`, serveTestingStaticRequest(router, `/assets/.well-known/security.txt`, nil).Body.String())
+}
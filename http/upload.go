@@ -0,0 +1,132 @@
+package http
+
+import (
+	"github.com/firmeve/firmeve/kernel"
+	"github.com/firmeve/firmeve/kernel/contract"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type (
+	uploadedFile struct {
+		header   *multipart.FileHeader
+		mimeType string
+	}
+
+	// A disk storing the files under the root directory
+	LocalDisk string
+)
+
+func NewUploadedFile(header *multipart.FileHeader) contract.UploadedFile {
+	return &uploadedFile{
+		header: header,
+	}
+}
+
+func (u *uploadedFile) Filename() string {
+	return u.header.Filename
+}
+
+func (u *uploadedFile) Size() int64 {
+	return u.header.Size
+}
+
+// Detect the mime type from the first 512 bytes, the Content-Type of the client is not trusted
+func (u *uploadedFile) MimeType() (string, error) {
+	if u.mimeType != `` {
+		return u.mimeType, nil
+	}
+
+	file, err := u.Open()
+	if err != nil {
+		return ``, err
+	}
+	defer file.Close()
+
+	buf := make([]byte, 512)
+	n, err := io.ReadFull(file, buf)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return ``, err
+	}
+	u.mimeType = strings.Split(http.DetectContentType(buf[:n]), `;`)[0]
+
+	return u.mimeType, nil
+}
+
+func (u *uploadedFile) FileHeader() *multipart.FileHeader {
+	return u.header
+}
+
+func (u *uploadedFile) Open() (multipart.File, error) {
+	return u.header.Open()
+}
+
+// The errors carry the status meta, 413 for too large files and 415 for unsupported mime types
+func (u *uploadedFile) Check(maxSize int64, mimeTypes ...string) error {
+	if maxSize > 0 && u.Size() > maxSize {
+		err := kernel.Errorf("the file %s exceeds %d bytes", u.Filename(), maxSize)
+		err.SetMeta(`status`, http.StatusRequestEntityTooLarge)
+		return err
+	}
+
+	if len(mimeTypes) == 0 {
+		return nil
+	}
+
+	mimeType, err := u.MimeType()
+	if err != nil {
+		return err
+	}
+	for _, v := range mimeTypes {
+		if v == mimeType || (strings.HasSuffix(v, `/*`) && strings.HasPrefix(mimeType, strings.TrimSuffix(v, `*`))) {
+			return nil
+		}
+	}
+
+	mimeErr := kernel.Errorf("the mime type %s of the file %s is not allowed", mimeType, u.Filename())
+	mimeErr.SetMeta(`status`, http.StatusUnsupportedMediaType)
+	return mimeErr
+}
+
+func (u *uploadedFile) Store(disk contract.Disk, path string) error {
+	file, err := u.Open()
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return disk.Put(path, file)
+}
+
+// Write the file under the root, paths escaping the root are rejected
+func (d LocalDisk) Put(path string, reader io.Reader) error {
+	root, err := filepath.Abs(string(d))
+	if err != nil {
+		return err
+	}
+
+	file := filepath.Join(root, filepath.FromSlash(path))
+	if !strings.HasPrefix(file, root+string(filepath.Separator)) {
+		return kernel.Errorf("the path %s is out of the disk", path)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	if _, err = io.Copy(f, reader); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
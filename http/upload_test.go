@@ -0,0 +1,94 @@
+package http
+
+import (
+	"bytes"
+	"github.com/firmeve/firmeve/kernel/contract"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestingUploadRequest(files map[string][]string) *http.Request {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	writer.WriteField(`name`, `simon`)
+	for key, names := range files {
+		for _, name := range names {
+			part, _ := writer.CreateFormFile(key, name)
+			if strings.HasSuffix(name, `.png`) {
+				part.Write([]byte("\x89PNG\x0D\x0A\x1A\x0A" + strings.Repeat(`0`, 64)))
+			} else {
+				part.Write([]byte(`hello ` + name))
+			}
+		}
+	}
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, `/upload`, body)
+	req.Header.Set(`Content-Type`, writer.FormDataContentType())
+
+	return req
+}
+
+func TestHttp_UploadedFile(t *testing.T) {
+	dir, err := ioutil.TempDir(``, `firmeve`)
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	protocol := NewHttp(newTestingUploadRequest(map[string][]string{
+		`avatar`:      {`avatar.png`},
+		`attachments`: {`a.txt`, `b.txt`},
+	}), httptest.NewRecorder()).(*Http)
+	protocol.SetMaxMemory(1 << 10)
+
+	avatar, err := protocol.UploadedFile(`avatar`)
+	assert.Nil(t, err)
+	assert.Equal(t, `avatar.png`, avatar.Filename())
+	assert.Equal(t, int64(72), avatar.Size())
+	mimeType, err := avatar.MimeType()
+	assert.Nil(t, err)
+	assert.Equal(t, `image/png`, mimeType)
+	assert.Nil(t, avatar.Check(1<<10, `image/*`))
+	assert.Nil(t, avatar.Check(0, `image/jpeg`, `image/png`))
+
+	err = avatar.Check(10)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, err.(contract.Error).Meta()[`status`])
+	err = avatar.Check(0, `text/plain`)
+	assert.Equal(t, http.StatusUnsupportedMediaType, err.(contract.Error).Meta()[`status`])
+
+	attachments, err := protocol.UploadedFiles(`attachments`)
+	assert.Nil(t, err)
+	assert.Len(t, attachments, 2)
+	assert.Nil(t, attachments[1].Store(LocalDisk(dir), `docs/b.txt`))
+	content, err := ioutil.ReadFile(filepath.Join(dir, `docs`, `b.txt`))
+	assert.Nil(t, err)
+	assert.Equal(t, `hello b.txt`, string(content))
+	assert.NotNil(t, attachments[0].Store(LocalDisk(dir), `../a.txt`))
+
+	_, err = protocol.UploadedFile(`missing`)
+	assert.Equal(t, http.ErrMissingFile, err)
+	_, err = protocol.UploadedFiles(`missing`)
+	assert.Equal(t, http.ErrMissingFile, err)
+	assert.Equal(t, []string{`simon`}, protocol.Values()[`name`])
+}
+
+func TestRouter_SetMaxMultipartMemory(t *testing.T) {
+	router := newTestingRouter().SetMaxMultipartMemory(1 << 10)
+	router.POST(`/upload`, func(c contract.Context) {
+		file, err := c.FormFile(`avatar`)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1<<10), c.Protocol().(*Http).maxMemory)
+		c.Render(http.StatusOK, file.Filename())
+		c.Next()
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newTestingUploadRequest(map[string][]string{`avatar`: {`avatar.png`}}))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
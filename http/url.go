@@ -0,0 +1,72 @@
+package http
+
+import (
+	"fmt"
+	"github.com/firmeve/firmeve/kernel"
+	"net/url"
+	"strings"
+)
+
+// The path of the named route. The params are key value pairs filling the path parameters,
+// the others are appended to the query, e.g. URL(`users.show`, `id`, 1, `tab`, `posts`) is /users/1?tab=posts
+func (r *Router) URL(name string, params ...interface{}) (string, error) {
+	if len(params)%2 != 0 {
+		return ``, kernel.Errorf("the params of the route %s must be key value pairs", name)
+	}
+
+	route, ok := r.names[name]
+	if !ok {
+		return ``, kernel.Errorf("the route %s does not exist", name)
+	}
+
+	values := make(map[string]string, len(params)/2)
+	keys := make([]string, 0, len(params)/2)
+	for i := 0; i < len(params); i += 2 {
+		key := fmt.Sprint(params[i])
+		values[key] = fmt.Sprint(params[i+1])
+		keys = append(keys, key)
+	}
+
+	segments := strings.Split(route.path, `/`)
+	for i, segment := range segments {
+		if segment == `` || (segment[0] != ':' && segment[0] != '*') {
+			continue
+		}
+
+		value, ok := values[segment[1:]]
+		if !ok {
+			return ``, kernel.Errorf("the param %s of the route %s is missing", segment[1:], name)
+		}
+		if segment[0] == ':' {
+			value = url.PathEscape(value)
+		} else {
+			value = strings.TrimPrefix(value, `/`)
+		}
+		segments[i] = value
+		delete(values, segment[1:])
+	}
+
+	query := make(url.Values, len(values))
+	for _, key := range keys {
+		if value, ok := values[key]; ok {
+			query.Add(key, value)
+		}
+	}
+
+	path := strings.Join(segments, `/`)
+	if len(query) > 0 {
+		path += `?` + query.Encode()
+	}
+
+	return path, nil
+}
+
+// Index the name of the route, including the child routes such as the create action of a resource.
+// A name given again belongs to the last route
+func (r *Router) nameRoute(name string, route *Route) {
+	if route.name != `` && r.names[route.name] == route {
+		delete(r.names, route.name)
+	}
+
+	r.names[name] = route
+}
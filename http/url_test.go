@@ -0,0 +1,92 @@
+package http
+
+import (
+	"github.com/firmeve/firmeve/kernel/contract"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRouter_URL(t *testing.T) {
+	router := newTestingRouter()
+	handler := func(c contract.Context) {}
+	router.GET(`/users/:id/posts/:post`, handler).Name(`users.posts`)
+	router.GET(`/files/*path`, handler).Name(`files`)
+	router.Group(`/admin`).GET(`/dashboard`, handler).Name(`admin.dashboard`)
+
+	path, err := router.URL(`users.posts`, `id`, 1, `post`, `a b`, `tab`, `new`, `page`, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, `/users/1/posts/a%20b?page=2&tab=new`, path)
+
+	path, err = router.URL(`files`, `path`, `/css/app.css`)
+	assert.Nil(t, err)
+	assert.Equal(t, `/files/css/app.css`, path)
+
+	path, err = router.URL(`admin.dashboard`)
+	assert.Nil(t, err)
+	assert.Equal(t, `/admin/dashboard`, path)
+
+	_, err = router.URL(`users.posts`, `id`, 1)
+	assert.NotNil(t, err)
+	_, err = router.URL(`users.posts`, `id`)
+	assert.NotNil(t, err)
+	_, err = router.URL(`missing`)
+	assert.NotNil(t, err)
+}
+
+func TestRouter_URL_Resource(t *testing.T) {
+	router := newTestingRouter()
+	router.Resource(`/photos`, new(mockPhotoController))
+	router.Group(`/api`).Resource(`/users/:user/comments`, new(mockCommentController))
+
+	path, err := router.URL(`photos.create`)
+	assert.Nil(t, err)
+	assert.Equal(t, `/photos/create`, path)
+
+	path, err = router.URL(`photos.edit`, `photo`, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, `/photos/1/edit`, path)
+
+	path, err = router.URL(`api.users.comments.create`, `user`, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, `/api/users/2/comments/create`, path)
+}
+
+func TestRoute_Name(t *testing.T) {
+	router := newTestingRouter()
+	handler := func(c contract.Context) {}
+	route := router.GET(`/users`, handler).Name(`users`)
+	assert.Same(t, route, route.Name(`users`))
+
+	// the last route given the name takes it
+	router.GET(`/members`, handler).Name(`users`)
+	path, err := router.URL(`users`)
+	assert.Nil(t, err)
+	assert.Equal(t, `/members`, path)
+
+	// the renamed route releases its name
+	route.Name(`members`)
+	path, err = router.URL(`members`)
+	assert.Nil(t, err)
+	assert.Equal(t, `/users`, path)
+	path, err = router.URL(`users`)
+	assert.Nil(t, err)
+	assert.Equal(t, `/members`, path)
+}
+
+func TestRouter_URL_GroupResource(t *testing.T) {
+	router := newTestingRouter()
+	router.Resource(`/photos`, new(mockPhotoController))
+	router.Group(`/admin`).Resource(`/photos`, new(mockPhotoController))
+
+	path, err := router.URL(`photos.show`, `photo`, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, `/photos/1`, path)
+
+	path, err = router.URL(`admin.photos.show`, `photo`, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, `/admin/photos/1`, path)
+
+	path, err = router.URL(`admin.photos.create`)
+	assert.Nil(t, err)
+	assert.Equal(t, `/admin/photos/create`, path)
+}
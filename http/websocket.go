@@ -0,0 +1,255 @@
+package http
+
+import (
+	"bytes"
+	"github.com/firmeve/firmeve/kernel"
+	"github.com/firmeve/firmeve/kernel/contract"
+	"github.com/firmeve/firmeve/support"
+	"github.com/gorilla/websocket"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type (
+	// The websocket protocol of a single frame, Bind reads the frame and Render writes a frame to the connection
+	WebSocket struct {
+		*webSocketConn
+		messageType int
+		message     []byte
+		reader      *bytes.Reader
+	}
+
+	webSocketConn struct {
+		conn    *websocket.Conn
+		request *http.Request
+		params  map[string]string
+		option  *webSocketOption
+		mutex   sync.Mutex
+	}
+
+	webSocketOption struct {
+		readLimit       int64
+		pingInterval    time.Duration
+		pongWait        time.Duration
+		writeWait       time.Duration
+		readBufferSize  int
+		writeBufferSize int
+		subprotocols    []string
+		checkOrigin     func(req *http.Request) bool
+	}
+)
+
+// The maximum size of a frame read from the client, the default is 1MB
+func WebSocketReadLimit(limit int64) support.Option {
+	return func(object support.Object) {
+		object.(*webSocketOption).readLimit = limit
+	}
+}
+
+// The interval of the pings and the time waiting for the pong, the connection is closed without pong
+func WebSocketPing(interval, pongWait time.Duration) support.Option {
+	return func(object support.Object) {
+		object.(*webSocketOption).pingInterval = interval
+		object.(*webSocketOption).pongWait = pongWait
+	}
+}
+
+// The timeout of writing a frame
+func WebSocketWriteWait(writeWait time.Duration) support.Option {
+	return func(object support.Object) {
+		object.(*webSocketOption).writeWait = writeWait
+	}
+}
+
+func WebSocketBufferSize(readBufferSize, writeBufferSize int) support.Option {
+	return func(object support.Object) {
+		object.(*webSocketOption).readBufferSize = readBufferSize
+		object.(*webSocketOption).writeBufferSize = writeBufferSize
+	}
+}
+
+func WebSocketSubprotocols(subprotocols ...string) support.Option {
+	return func(object support.Object) {
+		object.(*webSocketOption).subprotocols = subprotocols
+	}
+}
+
+// Check the Origin header of the handshake, cross origin requests are rejected by default
+func WebSocketCheckOrigin(checkOrigin func(req *http.Request) bool) support.Option {
+	return func(object support.Object) {
+		object.(*webSocketOption).checkOrigin = checkOrigin
+	}
+}
+
+// Upgrade the GET route to a websocket connection. The handshake runs the route middleware,
+// then every frame runs the same middleware with the handler in a new context on the connection goroutine
+func (r *Router) WebSocket(path string, handler contract.ContextHandler, options ...support.Option) *Route {
+	option := support.ApplyOption(&webSocketOption{
+		readLimit:    1 << 20,
+		pingInterval: 54 * time.Second,
+		pongWait:     60 * time.Second,
+		writeWait:    10 * time.Second,
+	}, options...).(*webSocketOption)
+
+	upgrader := &websocket.Upgrader{
+		ReadBufferSize:  option.readBufferSize,
+		WriteBufferSize: option.writeBufferSize,
+		Subprotocols:    option.subprotocols,
+		CheckOrigin:     option.checkOrigin,
+	}
+
+	var route *Route
+	route = r.GET(path, func(c contract.Context) {
+		protocol := c.Protocol().(*Http)
+		conn, err := upgrader.Upgrade(protocol.ResponseWriter(), protocol.Request(), nil)
+		if err != nil {
+			// The upgrader has responded with the handshake error
+			c.Abort()
+			return
+		}
+
+		// The frames run the middleware of the route with the handler in place of the upgrade,
+		// the models are bound once by the handshake and shared with the frames
+		frameRoute := *route
+		frameRoute.handler, frameRoute.compiled = handler, nil
+		(&webSocketConn{
+			conn:    conn,
+			request: protocol.Request(),
+			params:  protocol.Params(),
+			option:  option,
+		}).serve(r.Firmeve, r.middlewareHandlers(&frameRoute, r.boundModels(c)))
+
+		// The response writer is hijacked, the after handlers of the handshake can not respond
+		c.Abort()
+	})
+
+	return route
+}
+
+// Add the models bound by the handshake to the frame contexts
+func (r *Router) boundModels(c contract.Context) contract.ContextHandler {
+	entities := make([]*contract.ContextEntity, 0, len(r.models))
+	for param := range r.models {
+		if entity := c.Entity(param); entity != nil {
+			entities = append(entities, entity)
+		}
+	}
+
+	return func(c contract.Context) {
+		for _, entity := range entities {
+			c.AddEntity(entity.Key, entity.Value)
+		}
+		c.Next()
+	}
+}
+
+func (w *WebSocket) Name() string {
+	return `websocket`
+}
+
+func (w *WebSocket) Read(p []byte) (int, error) {
+	return w.reader.Read(p)
+}
+
+// The headers of the handshake request
+func (w *WebSocket) Metadata() map[string][]string {
+	return w.request.Header
+}
+
+// The current frame
+func (w *WebSocket) Message() ([]byte, error) {
+	return w.message, nil
+}
+
+// The query values of the handshake request
+func (w *WebSocket) Values() map[string][]string {
+	return w.request.URL.Query()
+}
+
+// Write a frame of the type of the current frame
+func (w *WebSocket) Write(p []byte) (int, error) {
+	if err := w.WriteMessage(w.messageType, p); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Write a frame, safe for concurrent use by the goroutines of the connection
+func (w *WebSocket) WriteMessage(messageType int, data []byte) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.conn.SetWriteDeadline(time.Now().Add(w.option.writeWait))
+
+	return w.conn.WriteMessage(messageType, data)
+}
+
+// The frame type, websocket.TextMessage or websocket.BinaryMessage
+func (w *WebSocket) MessageType() int {
+	return w.messageType
+}
+
+func (w *WebSocket) Conn() *websocket.Conn {
+	return w.conn
+}
+
+func (w *WebSocket) Request() *http.Request {
+	return w.request
+}
+
+func (w *WebSocket) Params() map[string]string {
+	return w.params
+}
+
+func (w *WebSocket) Param(key string) string {
+	return w.params[key]
+}
+
+// Read the frames until the connection is closed, the pings are sent by a goroutine of the connection
+func (c *webSocketConn) serve(firmeve contract.Application, handlers []contract.ContextHandler) {
+	defer c.conn.Close()
+
+	c.conn.SetReadLimit(c.option.readLimit)
+	c.conn.SetReadDeadline(time.Now().Add(c.option.pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(c.option.pongWait))
+	})
+
+	done := make(chan struct{})
+	defer close(done)
+	go c.ping(done)
+
+	for {
+		messageType, message, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		kernel.NewContext(firmeve, &WebSocket{
+			webSocketConn: c,
+			messageType:   messageType,
+			message:       message,
+			reader:        bytes.NewReader(message),
+		}, handlers...).Next()
+	}
+}
+
+func (c *webSocketConn) ping(done <-chan struct{}) {
+	ticker := time.NewTicker(c.option.pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			c.mutex.Lock()
+			err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(c.option.writeWait))
+			c.mutex.Unlock()
+			if err != nil {
+				return
+			}
+		}
+	}
+}
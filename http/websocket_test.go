@@ -0,0 +1,140 @@
+package http
+
+import (
+	"github.com/firmeve/firmeve/kernel/contract"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type mockWebSocketMessage struct {
+	Text string `json:"text"`
+}
+
+func dialTestingWebSocket(t *testing.T, server *httptest.Server, path string) *websocket.Conn {
+	conn, _, err := websocket.DefaultDialer.Dial(`ws`+strings.TrimPrefix(server.URL, `http`)+path, nil)
+	assert.Nil(t, err)
+
+	return conn
+}
+
+func TestRouter_WebSocket(t *testing.T) {
+	router := newTestingRouter()
+	var handshakes int32
+	router.Use(func(ctx contract.Context) {
+		if ctx.Protocol().Name() == `http` {
+			atomic.AddInt32(&handshakes, 1)
+		}
+		ctx.Next()
+	})
+	router.WebSocket(`/chat/:room`, func(ctx contract.Context) {
+		message := new(mockWebSocketMessage)
+		assert.Nil(t, ctx.Bind(message))
+		assert.Equal(t, `websocket`, ctx.Protocol().Name())
+		assert.Nil(t, ctx.Render(http.StatusOK, map[string]string{
+			`room`: ctx.Param(`room`),
+			`echo`: message.Text,
+			`user`: ctx.Get(`user`).([]string)[0],
+		}))
+		ctx.Next()
+	}).Before(func(ctx contract.Context) {
+		if ctx.Protocol().Name() == `websocket` && ctx.Protocol().(*WebSocket).MessageType() == websocket.BinaryMessage {
+			ctx.Protocol().Write([]byte(`binary frames are not supported`))
+			return
+		}
+		ctx.Next()
+	})
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	conn := dialTestingWebSocket(t, server, `/chat/go?user=simon`)
+	defer conn.Close()
+
+	for _, text := range []string{`hello`, `world`} {
+		assert.Nil(t, conn.WriteJSON(mockWebSocketMessage{Text: text}))
+		result := make(map[string]string)
+		assert.Nil(t, conn.ReadJSON(&result))
+		assert.Equal(t, map[string]string{`room`: `go`, `echo`: text, `user`: `simon`}, result)
+	}
+
+	assert.Nil(t, conn.WriteMessage(websocket.BinaryMessage, []byte(`{}`)))
+	messageType, message, err := conn.ReadMessage()
+	assert.Nil(t, err)
+	assert.Equal(t, websocket.BinaryMessage, messageType)
+	assert.Equal(t, `binary frames are not supported`, string(message))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&handshakes))
+}
+
+func TestRouter_WebSocket_Model(t *testing.T) {
+	router := newTestingRouter()
+	var resolved int32
+	router.Model(`room`, ModelResolver(func(c contract.Context, value string) (interface{}, error) {
+		atomic.AddInt32(&resolved, 1)
+		return &mockUser{Name: value}, nil
+	}))
+	router.WebSocket(`/chat/:room`, func(ctx contract.Context) {
+		ctx.Protocol().Write([]byte(ctx.Entity(`room`).Value.(*mockUser).Name))
+		ctx.Next()
+	})
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	conn := dialTestingWebSocket(t, server, `/chat/go`)
+	defer conn.Close()
+
+	// the model is bound by the handshake only
+	for i := 0; i < 2; i++ {
+		assert.Nil(t, conn.WriteMessage(websocket.TextMessage, []byte(`{}`)))
+		_, message, err := conn.ReadMessage()
+		assert.Nil(t, err)
+		assert.Equal(t, `go`, string(message))
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&resolved))
+}
+
+func TestRouter_WebSocket_Options(t *testing.T) {
+	router := newTestingRouter()
+	router.WebSocket(`/ws`, func(ctx contract.Context) {
+		ctx.Protocol().Write([]byte(`ok`))
+	}, WebSocketReadLimit(8), WebSocketPing(10*time.Millisecond, time.Second))
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	conn := dialTestingWebSocket(t, server, `/ws`)
+	defer conn.Close()
+
+	var pings int32
+	conn.SetPingHandler(func(string) error {
+		atomic.AddInt32(&pings, 1)
+		return nil
+	})
+
+	assert.Nil(t, conn.WriteMessage(websocket.TextMessage, []byte(`small`)))
+	_, message, err := conn.ReadMessage()
+	assert.Nil(t, err)
+	assert.Equal(t, `ok`, string(message))
+
+	// Control frames are handled while reading
+	conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	_, _, err = conn.ReadMessage()
+	assert.NotNil(t, err)
+	assert.True(t, atomic.LoadInt32(&pings) > 0)
+
+	// Frames over the read limit close the connection
+	conn = dialTestingWebSocket(t, server, `/ws`)
+	defer conn.Close()
+	assert.Nil(t, conn.WriteMessage(websocket.TextMessage, []byte(`over the read limit`)))
+	_, _, err = conn.ReadMessage()
+	assert.NotNil(t, err)
+
+	w := serveTestingRequest(router, http.MethodGet, `/ws`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,240 @@
+package input
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Form, query and header values are slices of strings, the scalars take the first value.
+// JSON arrays are not unwrapped, so that converting them fails
+func first(v interface{}) interface{} {
+	if value, ok := v.([]string); ok {
+		if len(value) == 0 {
+			return nil
+		}
+		return value[0]
+	}
+
+	return v
+}
+
+func toString(v interface{}) (string, error) {
+	switch value := first(v).(type) {
+	case string:
+		return value, nil
+	case json.Number:
+		return value.String(), nil
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(value), nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32:
+		return fmt.Sprint(value), nil
+	case nil:
+		return ``, fmt.Errorf("the value is empty")
+	}
+
+	return ``, fmt.Errorf("the value %T is not a string", v)
+}
+
+func toInt64(v interface{}) (int64, error) {
+	switch value := first(v).(type) {
+	case int:
+		return int64(value), nil
+	case int8:
+		return int64(value), nil
+	case int16:
+		return int64(value), nil
+	case int32:
+		return int64(value), nil
+	case int64:
+		return value, nil
+	case uint:
+		return int64(value), nil
+	case uint8:
+		return int64(value), nil
+	case uint16:
+		return int64(value), nil
+	case uint32:
+		return int64(value), nil
+	case uint64:
+		if value > math.MaxInt64 {
+			return 0, fmt.Errorf("the value %d overflows int64", value)
+		}
+		return int64(value), nil
+	case float64:
+		if value != math.Trunc(value) || value > math.MaxInt64 || value < math.MinInt64 {
+			return 0, fmt.Errorf("the value %v is not an integer", value)
+		}
+		return int64(value), nil
+	case json.Number:
+		return strconv.ParseInt(value.String(), 10, 64)
+	case string:
+		return strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	}
+
+	return 0, fmt.Errorf("the value %T is not an integer", v)
+}
+
+func toInt(v interface{}) (int, error) {
+	value, err := toInt64(v)
+	if err != nil {
+		return 0, err
+	} else if int64(int(value)) != value {
+		return 0, fmt.Errorf("the value %d overflows int", value)
+	}
+
+	return int(value), nil
+}
+
+func toUint(v interface{}) (uint, error) {
+	if value, ok := first(v).(uint64); ok {
+		if uint64(uint(value)) != value {
+			return 0, fmt.Errorf("the value %d overflows uint", value)
+		}
+		return uint(value), nil
+	}
+
+	value, err := toInt64(v)
+	if err != nil {
+		return 0, err
+	} else if value < 0 {
+		return 0, fmt.Errorf("the value %d is negative", value)
+	}
+
+	return uint(value), nil
+}
+
+func toFloat(v interface{}) (float64, error) {
+	switch value := first(v).(type) {
+	case float64:
+		return value, nil
+	case float32:
+		return float64(value), nil
+	case json.Number:
+		return value.Float64()
+	case string:
+		return strconv.ParseFloat(strings.TrimSpace(value), 64)
+	}
+
+	value, err := toInt64(v)
+	return float64(value), err
+}
+
+// Strings such as 1, true, on and yes are true, which covers the checkboxes of the forms
+func toBool(v interface{}) (bool, error) {
+	switch value := first(v).(type) {
+	case bool:
+		return value, nil
+	case string:
+		switch strings.ToLower(strings.TrimSpace(value)) {
+		case `on`, `yes`:
+			return true, nil
+		case `off`, `no`, ``:
+			return false, nil
+		}
+		return strconv.ParseBool(strings.TrimSpace(value))
+	}
+
+	value, err := toFloat(v)
+	return value != 0, err
+}
+
+// Strings are parsed with the layout, RFC 3339 by default, and integers are unix seconds
+func toTime(v interface{}, layout string) (time.Time, error) {
+	if layout == `` {
+		layout = time.RFC3339
+	}
+
+	switch value := first(v).(type) {
+	case time.Time:
+		return value, nil
+	case string:
+		t, err := time.Parse(layout, strings.TrimSpace(value))
+		if err == nil {
+			return t, nil
+		} else if _, intErr := strconv.ParseInt(strings.TrimSpace(value), 10, 64); intErr != nil {
+			return time.Time{}, err
+		}
+	}
+
+	seconds, err := toInt64(v)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(seconds, 0), nil
+}
+
+// Strings such as 1m30s are parsed as durations and numbers are seconds
+func toDuration(v interface{}) (time.Duration, error) {
+	switch value := first(v).(type) {
+	case time.Duration:
+		return value, nil
+	case string:
+		if duration, err := time.ParseDuration(strings.TrimSpace(value)); err == nil {
+			return duration, nil
+		}
+	}
+
+	seconds, err := toFloat(v)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+func toStrings(v interface{}) ([]string, error) {
+	switch value := v.(type) {
+	case []string:
+		return value, nil
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			s, err := toString(item)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, s)
+		}
+		return values, nil
+	}
+
+	value, err := toString(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{value}, nil
+}
+
+func toInts(v interface{}) ([]int, error) {
+	var items []interface{}
+	switch value := v.(type) {
+	case []string:
+		items = make([]interface{}, 0, len(value))
+		for _, item := range value {
+			items = append(items, item)
+		}
+	case []interface{}:
+		items = value
+	default:
+		items = []interface{}{v}
+	}
+
+	values := make([]int, 0, len(items))
+	for _, item := range items {
+		i, err := toInt(item)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, i)
+	}
+
+	return values, nil
+}
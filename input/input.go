@@ -1,74 +1,393 @@
 package input
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/firmeve/firmeve/input/parser"
+	"github.com/firmeve/firmeve/kernel/contract"
+	"time"
 )
 
 type (
+	// The values of the parsers, the first parser having the key takes precedence
 	Input struct {
-		Parser parser.IParser
+		Parsers []parser.IParser
+		err     error
 	}
 )
 
-func New(parser parser.IParser) *Input {
+var (
+	ErrMissing = errors.New(`the input is missing`)
+)
+
+func New(parsers ...parser.IParser) *Input {
 	return &Input{
-		Parser: parser,
+		Parsers: parsers,
 	}
 }
 
-func (i *Input) Bind(v interface{}) error {
-	return i.Parser.Bind(v)
+// The input of the protocol. The http body takes precedence over the query,
+// the other protocols read the JSON message and the values
+func FromProtocol(protocol contract.Protocol) *Input {
+	parsers := make([]parser.IParser, 0, 2)
+	p, ok := protocol.(contract.HttpProtocol)
+	if !ok {
+		if message, err := protocol.Message(); err == nil && json.Valid(message) {
+			parsers = append(parsers, parser.NewJSON(message))
+		}
+		return New(append(parsers, parser.NewForm(protocol.Values()))...)
+	}
+
+	var err error
+	switch p.ContentType() {
+	case contract.HttpMimeJson:
+		var message []byte
+		if message, err = p.Message(); err == nil && json.Valid(message) {
+			parsers = append(parsers, parser.NewJSON(message))
+		}
+	case contract.HttpMimeForm:
+		if err = p.ParseForm(); err == nil {
+			parsers = append(parsers, parser.NewForm(p.Request().PostForm))
+		}
+	case contract.HttpMimeMultipartForm:
+		if err = p.ParseForm(); err == nil {
+			parsers = append(parsers, parser.NewMultipartForm(p.Request().MultipartForm))
+		}
+	}
+
+	input := New(append(parsers, parser.NewForm(p.Request().URL.Query()))...)
+	input.err = err
+	return input
+}
+
+// The error of reading the body, such as the body exceeding the limit with the status 413
+func (i *Input) Err() error {
+	return i.err
 }
 
-//func (i *Input) Input(key string) interface{} {
-//	return i.parser.Get(key)
-//}
+// Bind the parsers in turn, so that the values of the first parser are bound last
+func (i *Input) Bind(v interface{}) error {
+	if i.err != nil {
+		return i.err
+	}
+
+	for j := len(i.Parsers) - 1; j >= 0; j-- {
+		if err := i.Parsers[j].Bind(v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
 
 func (i *Input) Has(key string) bool {
-	return i.Parser.Has(key)
+	for _, p := range i.Parsers {
+		if p.Has(key) {
+			return true
+		}
+	}
+
+	return false
 }
+
+// The raw value, nil when the key is missing
 func (i *Input) Get(key string) interface{} {
-	return i.Parser.Get(key)
+	for _, p := range i.Parsers {
+		if p.Has(key) {
+			return p.Get(key)
+		}
+	}
+
+	return nil
 }
 
-func (i *Input) GetString(key string) string {
-	return i.Get(key).(string)
+func (i *Input) GetString(key string) (string, error) {
+	value, err := i.value(key)
+	if err != nil {
+		return ``, err
+	}
+
+	v, err := toString(value)
+	return v, i.convertError(key, `a string`, err)
 }
 
-func (i *Input) GetInt(key string) int {
-	return i.Get(key).(int)
+func (i *Input) GetInt(key string) (int, error) {
+	value, err := i.value(key)
+	if err != nil {
+		return 0, err
+	}
+
+	v, err := toInt(value)
+	return v, i.convertError(key, `an integer`, err)
 }
 
-func (i *Input) GetUInt(key string) uint {
-	//v := i.Get(key)
-	//if value, ok := v.(string); ok {
-	//	intValue, _ := strconv.Atoi(value)
-	//	return uint(intValue)
-	//}
+func (i *Input) GetInt64(key string) (int64, error) {
+	value, err := i.value(key)
+	if err != nil {
+		return 0, err
+	}
 
-	return i.Get(key).(uint)
+	v, err := toInt64(value)
+	return v, i.convertError(key, `an integer`, err)
 }
 
-func (i *Input) GetFloat(key string) float64 {
-	return i.Get(key).(float64)
+func (i *Input) GetUint(key string) (uint, error) {
+	value, err := i.value(key)
+	if err != nil {
+		return 0, err
+	}
+
+	v, err := toUint(value)
+	return v, i.convertError(key, `an unsigned integer`, err)
 }
 
-func (i *Input) GetSliceString(key string) []string  {
-	return i.Get(key).([]string)
+func (i *Input) GetFloat(key string) (float64, error) {
+	value, err := i.value(key)
+	if err != nil {
+		return 0, err
+	}
+
+	v, err := toFloat(value)
+	return v, i.convertError(key, `a number`, err)
 }
 
-func (i *Input) GetSliceInt(key string) []int  {
-	return i.Get(key).([]int)
+func (i *Input) GetBool(key string) (bool, error) {
+	value, err := i.value(key)
+	if err != nil {
+		return false, err
+	}
+
+	v, err := toBool(value)
+	return v, i.convertError(key, `a boolean`, err)
 }
 
-func (i *Input) GetSliceFloat(key string) []float64  {
-	return i.Get(key).([]float64)
+// Strings are parsed with the layout, RFC 3339 when the layout is empty, and numbers are unix seconds
+func (i *Input) GetTime(key string, layout string) (time.Time, error) {
+	value, err := i.value(key)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	v, err := toTime(value, layout)
+	return v, i.convertError(key, `a time`, err)
+}
+
+// Strings such as 1m30s are parsed as durations and numbers are seconds
+func (i *Input) GetDuration(key string) (time.Duration, error) {
+	value, err := i.value(key)
+	if err != nil {
+		return 0, err
+	}
+
+	v, err := toDuration(value)
+	return v, i.convertError(key, `a duration`, err)
+}
+
+func (i *Input) GetStrings(key string) ([]string, error) {
+	value, err := i.value(key)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := toStrings(value)
+	return v, i.convertError(key, `a list of strings`, err)
 }
 
-func (i *Input) GetMapString(key string) map[string]string  {
-	return i.Get(key).(map[string]string)
+func (i *Input) GetInts(key string) ([]int, error) {
+	value, err := i.value(key)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := toInts(value)
+	return v, i.convertError(key, `a list of integers`, err)
 }
 
-func (i *Input) GetMapInterface(key string) map[string]interface{}  {
-	return i.Get(key).(map[string]interface{})
+func (i *Input) GetMap(key string) (map[string]interface{}, error) {
+	value, err := i.value(key)
+	if err != nil {
+		return nil, err
+	}
+
+	v, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, i.convertError(key, `an object`, fmt.Errorf("the value %T is not an object", value))
+	}
+
+	return v, nil
+}
+
+func (i *Input) GetStringDefault(key string, value string) string {
+	if v, err := i.GetString(key); err == nil {
+		return v
+	}
+
+	return value
+}
+
+func (i *Input) GetIntDefault(key string, value int) int {
+	if v, err := i.GetInt(key); err == nil {
+		return v
+	}
+
+	return value
+}
+
+func (i *Input) GetInt64Default(key string, value int64) int64 {
+	if v, err := i.GetInt64(key); err == nil {
+		return v
+	}
+
+	return value
+}
+
+func (i *Input) GetUintDefault(key string, value uint) uint {
+	if v, err := i.GetUint(key); err == nil {
+		return v
+	}
+
+	return value
+}
+
+func (i *Input) GetFloatDefault(key string, value float64) float64 {
+	if v, err := i.GetFloat(key); err == nil {
+		return v
+	}
+
+	return value
+}
+
+func (i *Input) GetBoolDefault(key string, value bool) bool {
+	if v, err := i.GetBool(key); err == nil {
+		return v
+	}
+
+	return value
+}
+
+func (i *Input) GetTimeDefault(key string, layout string, value time.Time) time.Time {
+	if v, err := i.GetTime(key, layout); err == nil {
+		return v
+	}
+
+	return value
+}
+
+func (i *Input) GetDurationDefault(key string, value time.Duration) time.Duration {
+	if v, err := i.GetDuration(key); err == nil {
+		return v
+	}
+
+	return value
+}
+
+// The string of the key, the default or empty when the value is missing or inconvertible
+func (i *Input) String(key string, value ...string) string {
+	var v string
+	if len(value) > 0 {
+		v = value[0]
+	}
+
+	return i.GetStringDefault(key, v)
+}
+
+// The integer of the key, the default or zero when the value is missing or inconvertible, e.g. Int(`page`, 1)
+func (i *Input) Int(key string, value ...int) int {
+	var v int
+	if len(value) > 0 {
+		v = value[0]
+	}
+
+	return i.GetIntDefault(key, v)
+}
+
+func (i *Input) Int64(key string, value ...int64) int64 {
+	var v int64
+	if len(value) > 0 {
+		v = value[0]
+	}
+
+	return i.GetInt64Default(key, v)
+}
+
+func (i *Input) Uint(key string, value ...uint) uint {
+	var v uint
+	if len(value) > 0 {
+		v = value[0]
+	}
+
+	return i.GetUintDefault(key, v)
+}
+
+func (i *Input) Float(key string, value ...float64) float64 {
+	var v float64
+	if len(value) > 0 {
+		v = value[0]
+	}
+
+	return i.GetFloatDefault(key, v)
+}
+
+func (i *Input) Bool(key string, value ...bool) bool {
+	var v bool
+	if len(value) > 0 {
+		v = value[0]
+	}
+
+	return i.GetBoolDefault(key, v)
+}
+
+func (i *Input) Time(key string, layout string, value ...time.Time) time.Time {
+	var v time.Time
+	if len(value) > 0 {
+		v = value[0]
+	}
+
+	return i.GetTimeDefault(key, layout, v)
+}
+
+func (i *Input) Duration(key string, value ...time.Duration) time.Duration {
+	var v time.Duration
+	if len(value) > 0 {
+		v = value[0]
+	}
+
+	return i.GetDurationDefault(key, v)
+}
+
+// The strings of the key, nil when the value is missing or inconvertible
+func (i *Input) Strings(key string) []string {
+	v, _ := i.GetStrings(key)
+	return v
+}
+
+func (i *Input) Ints(key string) []int {
+	v, _ := i.GetInts(key)
+	return v
+}
+
+func (i *Input) Map(key string) map[string]interface{} {
+	v, _ := i.GetMap(key)
+	return v
+}
+
+func (i *Input) value(key string) (interface{}, error) {
+	if !i.Has(key) {
+		// The unread body may have had the key
+		if i.err != nil {
+			return nil, i.err
+		}
+		return nil, fmt.Errorf("%w: %s", ErrMissing, key)
+	}
+
+	return i.Get(key), nil
+}
+
+func (i *Input) convertError(key, kind string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return fmt.Errorf("the input %s is not %s: %w", key, kind, err)
 }
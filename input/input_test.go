@@ -1,7 +1,17 @@
 package input
 
 import (
+	"errors"
+	"github.com/firmeve/firmeve/input/parser"
+	"github.com/firmeve/firmeve/kernel/contract"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
 )
 
 type MockJson struct {
@@ -23,4 +33,129 @@ func TestNew(t *testing.T) {
 	//fmt.Println("===========")
 	//fmt.Println(input.GetFloat(`type`) == 1)
 	//fmt.Println(input.GetSliceString(`exchanges`))
-}
\ No newline at end of file
+}
+
+func newTestingJSONInput() *Input {
+	return New(parser.NewJSON([]byte(`{"id":4787,"price":"1995.50","big":9007199254740993,"paid":true,"tags":["a","b"],"coupons":[1,"2",3],
+"created_at":"2019-11-29T14:11:04Z","timeout":"1m30s","user":{"id":743,"address":{"city":"hangzhou"}},"products":[{"title":"battery"}],
+"a.b":"dotted","refund":null}`)))
+}
+
+func TestInput_JSON(t *testing.T) {
+	input := newTestingJSONInput()
+	assert.Equal(t, 4787, input.Int(`id`))
+	assert.Equal(t, int64(9007199254740993), input.Int64(`big`))
+	assert.Equal(t, uint(743), input.Uint(`user.id`))
+	assert.Equal(t, 1995.5, input.Float(`price`))
+	assert.Equal(t, `4787`, input.String(`id`))
+	assert.Equal(t, `hangzhou`, input.String(`user.address.city`))
+	assert.Equal(t, `battery`, input.String(`products.0.title`))
+	assert.Equal(t, `dotted`, input.String(`a.b`))
+	assert.Equal(t, true, input.Bool(`paid`))
+	assert.Equal(t, []string{`a`, `b`}, input.Strings(`tags`))
+	assert.Equal(t, []int{1, 2, 3}, input.Ints(`coupons`))
+	assert.Equal(t, 90*time.Second, input.Duration(`timeout`))
+	assert.Equal(t, time.Date(2019, 11, 29, 14, 11, 4, 0, time.UTC), input.Time(`created_at`, ``))
+	assert.Equal(t, `hangzhou`, input.Map(`user.address`)[`city`])
+	assert.True(t, input.Has(`refund`))
+	assert.Nil(t, input.Get(`refund`))
+
+	// missing and inconvertible values
+	assert.False(t, input.Has(`user.name`))
+	assert.Nil(t, input.Get(`products.1.title`))
+	assert.Equal(t, 1, input.Int(`page`, 1))
+	assert.Equal(t, 0, input.Int(`page`))
+	assert.Equal(t, 10, input.GetIntDefault(`tags`, 10))
+	assert.Equal(t, 0, input.Int(`price`))
+	assert.Equal(t, uint(0), input.Uint(`refund`))
+	assert.Nil(t, input.Map(`tags`))
+	_, err := input.GetInt(`page`)
+	assert.True(t, errors.Is(err, ErrMissing))
+	_, err = input.GetInt(`price`)
+	assert.EqualError(t, err, `the input price is not an integer: strconv.ParseInt: parsing "1995.50": invalid syntax`)
+	_, err = input.GetString(`user`)
+	assert.NotNil(t, err)
+	// the JSON arrays are not scalars, unlike the values of the forms
+	_, err = input.GetInt(`coupons`)
+	assert.NotNil(t, err)
+	_, err = input.GetString(`tags`)
+	assert.NotNil(t, err)
+
+	assert.Empty(t, New(parser.NewJSON([]byte(`[1`))).String(`id`))
+}
+
+func TestInput_Form(t *testing.T) {
+	input := New(parser.NewForm(url.Values{
+		`page`:     {`2`},
+		`ids`:      {`1`, `2`},
+		`remember`: {`on`},
+		`price`:    {` 9.9 `},
+		`at`:       {`1575000000`},
+		`date`:     {`2019-11-29`},
+		`timeout`:  {`30`},
+		`user.id`:  {`3`},
+	}), parser.NewForm(url.Values{`page`: {`1`}, `sort`: {`id`}}))
+
+	assert.Equal(t, 2, input.Int(`page`, 1))
+	assert.Equal(t, `id`, input.String(`sort`))
+	assert.Equal(t, 1, input.Int(`ids`))
+	assert.Equal(t, []int{1, 2}, input.Ints(`ids`))
+	assert.Equal(t, []string{`1`, `2`}, input.Strings(`ids`))
+	assert.True(t, input.Bool(`remember`))
+	assert.False(t, input.Bool(`missing`))
+	assert.Equal(t, 9.9, input.Float(`price`))
+	assert.Equal(t, int64(1575000000), input.Time(`at`, ``).Unix())
+	assert.Equal(t, time.Date(2019, 11, 29, 0, 0, 0, 0, time.UTC), input.Time(`date`, `2006-01-02`))
+	assert.Equal(t, 30*time.Second, input.Duration(`timeout`))
+	assert.Equal(t, 3, input.Int(`user.id`))
+	assert.Equal(t, uint(5), input.GetUintDefault(`sort`, 5))
+
+	v := new(struct {
+		Page int    `form:"page"`
+		Sort string `form:"sort"`
+	})
+	assert.Nil(t, input.Bind(v))
+	assert.Equal(t, 2, v.Page)
+	assert.Equal(t, `id`, v.Sort)
+}
+
+type mockInputProtocol struct {
+	contract.HttpProtocol
+	request *http.Request
+}
+
+func (m *mockInputProtocol) Request() *http.Request {
+	return m.request
+}
+
+func (m *mockInputProtocol) ContentType() string {
+	return m.request.Header.Get(`Content-Type`)
+}
+
+func (m *mockInputProtocol) Message() ([]byte, error) {
+	return ioutil.ReadAll(m.request.Body)
+}
+
+func (m *mockInputProtocol) Values() map[string][]string {
+	m.request.ParseForm()
+	return m.request.Form
+}
+
+func (m *mockInputProtocol) ParseForm() error {
+	return m.request.ParseForm()
+}
+
+func TestFromProtocol(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, `/?page=1&sort=id`, strings.NewReader(`{"page":2,"user":{"name":"simon"}}`))
+	req.Header.Set(`Content-Type`, contract.HttpMimeJson)
+	input := FromProtocol(&mockInputProtocol{request: req})
+	assert.Equal(t, 2, input.Int(`page`))
+	assert.Equal(t, `id`, input.String(`sort`))
+	assert.Equal(t, `simon`, input.String(`user.name`))
+
+	req = httptest.NewRequest(http.MethodPost, `/?page=1&sort=id`, strings.NewReader(`page=3`))
+	req.Header.Set(`Content-Type`, contract.HttpMimeForm)
+	input = FromProtocol(&mockInputProtocol{request: req})
+	assert.Equal(t, 3, input.Int(`page`))
+	assert.Equal(t, `id`, input.String(`sort`))
+}
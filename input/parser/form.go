@@ -21,8 +21,13 @@ func (j *Form) Has(key string) bool {
 	return ok
 }
 
+// The values of the key, nil when the key is missing
 func (j *Form) Get(key string) interface{} {
-	return j.original.Get(key)
+	if values, ok := j.original[key]; ok {
+		return values
+	}
+
+	return nil
 }
 
 func NewForm(data FormData) *Form {
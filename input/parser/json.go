@@ -1,6 +1,11 @@
 package parser
 
-import "encoding/json"
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
 
 type (
 	JSONData map[string]interface{}
@@ -16,28 +21,58 @@ func (j *JSON) Bind(object interface{}) error {
 }
 
 func (j *JSON) Has(key string) bool {
-	_, ok := j.data[key]
+	_, ok := j.lookup(key)
 	return ok
 }
 
+// The value of the key or the dotted path into the nested objects and arrays, e.g. user.address.city or products.0.title.
+// Numbers are json.Number, nil when the key is missing
 func (j *JSON) Get(key string) interface{} {
-	if j.Has(key) {
-		return j.data[key]
+	value, _ := j.lookup(key)
+	return value
+}
+
+// Keys containing dots are matched before the path
+func (j *JSON) lookup(key string) (interface{}, bool) {
+	if value, ok := j.data[key]; ok {
+		return value, true
+	}
+
+	var current interface{} = map[string]interface{}(j.data)
+	for _, segment := range strings.Split(key, `.`) {
+		switch value := current.(type) {
+		case map[string]interface{}:
+			next, ok := value[segment]
+			if !ok {
+				return nil, false
+			}
+			current = next
+		case []interface{}:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(value) {
+				return nil, false
+			}
+			current = value[index]
+		default:
+			return nil, false
+		}
 	}
 
-	return ``
+	return current, true
 }
 
+// Messages which are not JSON objects have no values
 func (j *JSON) parse() {
-	if err := json.Unmarshal(j.original, &j.data); err != nil {
-		panic(err)
+	decoder := json.NewDecoder(bytes.NewReader(j.original))
+	decoder.UseNumber()
+	if err := decoder.Decode(&j.data); err != nil || j.data == nil {
+		j.data = make(JSONData, 0)
 	}
 }
 
 func NewJSON(data []byte) *JSON {
 	j := &JSON{
 		original: data,
-		data:     make(JSONData, 0),
 	}
 	j.parse()
 	return j
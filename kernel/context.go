@@ -1,9 +1,16 @@
 package kernel
 
 import (
+	context2 "context"
+	"errors"
 	"github.com/firmeve/firmeve/binding"
+	"github.com/firmeve/firmeve/input"
 	"github.com/firmeve/firmeve/kernel/contract"
 	"github.com/firmeve/firmeve/render"
+	uuid "github.com/iris-contrib/go.uuid"
+	"io"
+	"net/http"
+	"strconv"
 	"time"
 )
 
@@ -12,12 +19,21 @@ const (
 )
 
 type (
+	requestProtocol interface {
+		Request() *http.Request
+	}
+
+	contextProtocol interface {
+		Context() context2.Context
+	}
+
 	context struct {
 		firmeve  contract.Application
 		protocol contract.Protocol
 		handlers []contract.ContextHandler
 		entries  map[string]*contract.ContextEntity
 		index    int
+		input    contract.Input
 	}
 )
 
@@ -86,6 +102,16 @@ func (c *context) Entity(key string) *contract.ContextEntity {
 	return nil
 }
 
+// The input of the protocol, parsed once for the context
+func (c *context) Input() contract.Input {
+	if c.input == nil {
+		c.input = input.FromProtocol(c.protocol)
+		c.bodyError(c.input.Err())
+	}
+
+	return c.input
+}
+
 func (c *context) Get(key string) interface{} {
 	values := c.protocol.Values()
 	if value, ok := values[key]; ok {
@@ -95,12 +121,56 @@ func (c *context) Get(key string) interface{} {
 	return nil
 }
 
+// The route parameter, empty when the protocol has no route parameters
+func (c *context) Param(key string) string {
+	if p, ok := c.protocol.(interface{ Param(key string) string }); ok {
+		return p.Param(key)
+	}
+
+	return ``
+}
+
+func (c *context) ParamInt(key string) (int, error) {
+	value, err := strconv.Atoi(c.Param(key))
+	if err != nil {
+		return 0, Errorf("the param %s is not an integer", key)
+	}
+
+	return value, nil
+}
+
+func (c *context) ParamUUID(key string) (uuid.UUID, error) {
+	value, err := uuid.FromString(c.Param(key))
+	if err != nil {
+		return uuid.Nil, Errorf("the param %s is not an uuid", key)
+	}
+
+	return value, nil
+}
+
+// A body exceeding the limit is answered with 413 Request Entity Too Large and aborts the context,
+// so that the handler only has to return the error
 func (c *context) Bind(v interface{}) error {
-	return binding.Bind(c.protocol, v)
+	return c.bodyError(binding.Bind(c.protocol, v))
+}
+
+// Decode the items of a JSON array body one by one into v, fn is called after every item
+func (c *context) BindEach(v interface{}, fn func() error) error {
+	return c.bodyError(binding.Each(c.protocol, v, fn))
 }
 
 func (c *context) BindWith(b contract.Binding, v interface{}) error {
-	return b.Protocol(c.protocol, v)
+	return c.bodyError(b.Protocol(c.protocol, v))
+}
+
+func (c *context) bodyError(err error) error {
+	var e contract.Error
+	if errors.As(err, &e) && e.Meta()[`status`] == http.StatusRequestEntityTooLarge {
+		c.Error(http.StatusRequestEntityTooLarge, err)
+		c.Abort()
+	}
+
+	return err
 }
 
 func (c *context) RenderWith(status int, r contract.Render, v interface{}) error {
@@ -111,6 +181,71 @@ func (c *context) Render(status int, v interface{}) error {
 	return render.Render(c.protocol, status, v)
 }
 
+// Render the value of the type preferred by the Accept header, the keys are the offered types
+func (c *context) Negotiate(status int, values map[string]interface{}) error {
+	return render.RenderOffers(c.protocol, status, values)
+}
+
+// Render the file, range and conditional requests are answered with partial content or not modified
+func (c *context) File(path string) error {
+	return c.RenderWith(http.StatusOK, render.File, path)
+}
+
+// Render the file as a download named filename
+func (c *context) Attachment(path string, filename string) error {
+	return c.RenderWith(http.StatusOK, render.File, render.Attachment{
+		Path:     path,
+		Filename: filename,
+	})
+}
+
+// Stream the reader to the client chunk by chunk
+func (c *context) Stream(reader io.Reader, contentType string) error {
+	return c.RenderWith(http.StatusOK, render.Stream, render.StreamReader{
+		Reader:      reader,
+		ContentType: contentType,
+	})
+}
+
+// Push server-sent events until the handler returns, the handler should return once Done is closed
+func (c *context) SSE(handler func(emit func(event, data string)) error) error {
+	return c.RenderWith(http.StatusOK, render.SSE, render.SSEStream{
+		Context: c,
+		Handler: handler,
+	})
+}
+
+// Render the html template with the view bound as `view`
+func (c *context) View(status int, name string, data interface{}) error {
+	if !c.firmeve.Has(`view`) {
+		return Errorf("the view is not registered")
+	}
+
+	return c.RenderWith(status, render.HTML, render.HTMLTemplate{
+		View:    c.firmeve.Get(`view`).(contract.View),
+		Context: c,
+		Name:    name,
+		Data:    data,
+	})
+}
+
+// The uploaded file of the multipart form, named after http.Request.FormFile as File renders a file
+func (c *context) FormFile(key string) (contract.UploadedFile, error) {
+	if p, ok := c.protocol.(contract.HttpProtocol); ok {
+		return p.UploadedFile(key)
+	}
+
+	return nil, Errorf("the protocol %s does not support uploaded files", c.protocol.Name())
+}
+
+func (c *context) FormFiles(key string) ([]contract.UploadedFile, error) {
+	if p, ok := c.protocol.(contract.HttpProtocol); ok {
+		return p.UploadedFiles(key)
+	}
+
+	return nil, Errorf("the protocol %s does not support uploaded files", c.protocol.Name())
+}
+
 func (c *context) Clone() contract.Context {
 	//@todo 暂时先返回自己，Context全部完善后再修改clone
 	return c
@@ -118,15 +253,29 @@ func (c *context) Clone() contract.Context {
 
 // --------------------------- context.Context -> Base context ------------------------
 
+// Protocols of a request, such as http, websocket and grpc, delegate to the request context,
+// which is canceled when the client disconnects
 func (c *context) Deadline() (deadline time.Time, ok bool) {
+	if ctx := c.baseContext(); ctx != nil {
+		return ctx.Deadline()
+	}
+
 	return
 }
 
 func (c *context) Done() <-chan struct{} {
+	if ctx := c.baseContext(); ctx != nil {
+		return ctx.Done()
+	}
+
 	return nil
 }
 
 func (c *context) Err() error {
+	if ctx := c.baseContext(); ctx != nil {
+		return ctx.Err()
+	}
+
 	return nil
 }
 
@@ -137,3 +286,14 @@ func (c *context) Value(key interface{}) interface{} {
 
 	return nil
 }
+
+func (c *context) baseContext() context2.Context {
+	switch p := c.protocol.(type) {
+	case requestProtocol:
+		return p.Request().Context()
+	case contextProtocol:
+		return p.Context()
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package kernel
+
+import (
+	context2 "context"
+	"github.com/firmeve/firmeve/kernel/contract"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type mockParamProtocol struct {
+	contract.HttpProtocol
+	params map[string]string
+}
+
+func (m *mockParamProtocol) Param(key string) string {
+	return m.params[key]
+}
+
+func TestContext_Param(t *testing.T) {
+	ctx := NewContext(New(), &mockParamProtocol{params: map[string]string{
+		`id`:   `12`,
+		`name`: `firmeve`,
+		`uuid`: `6ba7b810-9dad-11d1-80b4-00c04fd430c8`,
+	}})
+
+	assert.Equal(t, `firmeve`, ctx.Param(`name`))
+
+	id, err := ctx.ParamInt(`id`)
+	assert.Nil(t, err)
+	assert.Equal(t, 12, id)
+	_, err = ctx.ParamInt(`name`)
+	assert.NotNil(t, err)
+
+	uuid, err := ctx.ParamUUID(`uuid`)
+	assert.Nil(t, err)
+	assert.Equal(t, `6ba7b810-9dad-11d1-80b4-00c04fd430c8`, uuid.String())
+	_, err = ctx.ParamUUID(`id`)
+	assert.NotNil(t, err)
+}
+
+type mockRequestProtocol struct {
+	contract.HttpProtocol
+	request *http.Request
+}
+
+func (m *mockRequestProtocol) Request() *http.Request {
+	return m.request
+}
+
+func TestContext_Done(t *testing.T) {
+	requestCtx, cancel := context2.WithCancel(context2.Background())
+	ctx := NewContext(New(), &mockRequestProtocol{
+		request: httptest.NewRequest(http.MethodGet, `/`, nil).WithContext(requestCtx),
+	})
+
+	assert.Nil(t, ctx.Err())
+	cancel()
+	<-ctx.Done()
+	assert.Equal(t, context2.Canceled, ctx.Err())
+
+	assert.Nil(t, NewContext(New(), nil).Done())
+}
+
+type mockMessageProtocol struct {
+	contract.Protocol
+	message []byte
+}
+
+func (m *mockMessageProtocol) Message() ([]byte, error) {
+	return m.message, nil
+}
+
+func (m *mockMessageProtocol) Name() string {
+	return `message`
+}
+
+func (m *mockMessageProtocol) Values() map[string][]string {
+	return map[string][]string{`args`: {`a`, `b`}}
+}
+
+func TestContext_Input(t *testing.T) {
+	ctx := NewContext(New(), &mockMessageProtocol{message: []byte(`{"page":"2","user":{"id":3}}`)})
+	assert.Equal(t, 2, ctx.Input().Int(`page`, 1))
+	assert.Equal(t, 3, ctx.Input().Int(`user.id`))
+	assert.Equal(t, 20, ctx.Input().Int(`size`, 20))
+	assert.Equal(t, []string{`a`, `b`}, ctx.Input().Strings(`args`))
+	assert.Same(t, ctx.Input(), ctx.Input())
+}
+
+func TestContext_FormFile(t *testing.T) {
+	ctx := NewContext(New(), &mockMessageProtocol{})
+	_, err := ctx.FormFile(`avatar`)
+	assert.NotNil(t, err)
+	_, err = ctx.FormFiles(`attachments`)
+	assert.NotNil(t, err)
+}
@@ -3,7 +3,9 @@ package contract
 import (
 	"context"
 	//"encoding/json"
-	//"io"
+	"io"
+
+	uuid "github.com/iris-contrib/go.uuid"
 )
 
 type (
@@ -37,12 +39,38 @@ type (
 
 		BindWith(b Binding, v interface{}) error
 
+		BindEach(v interface{}, fn func() error) error
+
 		Get(key string) interface{}
 
+		Input() Input
+
+		Param(key string) string
+
+		ParamInt(key string) (int, error)
+
+		ParamUUID(key string) (uuid.UUID, error)
+
 		Render(status int, v interface{}) error
 
 		RenderWith(status int, r Render, v interface{}) error
 
+		Negotiate(status int, values map[string]interface{}) error
+
+		File(path string) error
+
+		Attachment(path string, filename string) error
+
+		Stream(reader io.Reader, contentType string) error
+
+		SSE(handler func(emit func(event, data string)) error) error
+
+		View(status int, name string, data interface{}) error
+
+		FormFile(key string) (UploadedFile, error)
+
+		FormFiles(key string) ([]UploadedFile, error)
+
 		Clone() Context
 	}
 )
@@ -1,11 +1,21 @@
 package contract
 
-import "net/http"
+import (
+	"io"
+	"mime/multipart"
+	"net/http"
+)
 
 const (
 	HttpMimeJson          = "application/json"
 	HttpMimeHtml          = "text/html"
 	HttpMimeXml           = "application/xml"
+	HttpMimeXml2          = "text/xml"
+	HttpMimeYaml          = "application/x-yaml"
+	HttpMimeYaml2         = "application/yaml"
+	HttpMimeMsgPack       = "application/x-msgpack"
+	HttpMimeMsgPack2      = "application/msgpack"
+	HttpMimeProtobuf      = "application/x-protobuf"
 	HttpMimePlain         = "text/plain"
 	HttpMimeForm          = "application/x-www-form-urlencoded"
 	HttpMimeMultipartForm = "multipart/form-data"
@@ -40,5 +50,42 @@ type (
 		Cookie(name string) (string, error)
 
 		Redirect(status int, location string)
+
+		Params() map[string]string
+
+		Param(key string) string
+
+		// Parse the form or the multipart form body, Values is empty when it fails,
+		// such as the body exceeding the limit with the status 413
+		ParseForm() error
+
+		UploadedFile(key string) (UploadedFile, error)
+
+		UploadedFiles(key string) ([]UploadedFile, error)
+	}
+
+	// A file uploaded by a multipart form
+	UploadedFile interface {
+		// The filename of the client
+		Filename() string
+
+		Size() int64
+
+		// The mime type detected from the content
+		MimeType() (string, error)
+
+		FileHeader() *multipart.FileHeader
+
+		Open() (multipart.File, error)
+
+		// Check the size and the mime type, mime types such as image/* match any subtype
+		Check(maxSize int64, mimeTypes ...string) error
+
+		Store(disk Disk, path string) error
+	}
+
+	// The storage of the uploaded files
+	Disk interface {
+		Put(path string, reader io.Reader) error
 	}
 )
@@ -0,0 +1,78 @@
+package contract
+
+import "time"
+
+type (
+	// The input values of the protocol. The getters return an error for missing or inconvertible values,
+	// the shorthands such as Int(`page`, 1) return the default instead
+	Input interface {
+		// The error of reading the protocol, such as the request body exceeding the limit
+		Err() error
+
+		Has(key string) bool
+
+		Get(key string) interface{}
+
+		Bind(v interface{}) error
+
+		GetString(key string) (string, error)
+
+		GetInt(key string) (int, error)
+
+		GetInt64(key string) (int64, error)
+
+		GetUint(key string) (uint, error)
+
+		GetFloat(key string) (float64, error)
+
+		GetBool(key string) (bool, error)
+
+		GetTime(key string, layout string) (time.Time, error)
+
+		GetDuration(key string) (time.Duration, error)
+
+		GetStrings(key string) ([]string, error)
+
+		GetInts(key string) ([]int, error)
+
+		GetMap(key string) (map[string]interface{}, error)
+
+		GetStringDefault(key string, value string) string
+
+		GetIntDefault(key string, value int) int
+
+		GetInt64Default(key string, value int64) int64
+
+		GetUintDefault(key string, value uint) uint
+
+		GetFloatDefault(key string, value float64) float64
+
+		GetBoolDefault(key string, value bool) bool
+
+		GetTimeDefault(key string, layout string, value time.Time) time.Time
+
+		GetDurationDefault(key string, value time.Duration) time.Duration
+
+		String(key string, value ...string) string
+
+		Int(key string, value ...int) int
+
+		Int64(key string, value ...int64) int64
+
+		Uint(key string, value ...uint) uint
+
+		Float(key string, value ...float64) float64
+
+		Bool(key string, value ...bool) bool
+
+		Time(key string, layout string, value ...time.Time) time.Time
+
+		Duration(key string, value ...time.Duration) time.Duration
+
+		Strings(key string) []string
+
+		Ints(key string) []int
+
+		Map(key string) map[string]interface{}
+	}
+)
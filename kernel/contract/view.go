@@ -0,0 +1,10 @@
+package contract
+
+import "io"
+
+type (
+	View interface {
+		// Render the template of the name, the context may be nil outside of the requests
+		Render(c Context, w io.Writer, name string, data interface{}) error
+	}
+)
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/firmeve/firmeve/kernel/contract"
+	"github.com/firmeve/firmeve/render"
 	"runtime"
 	"strings"
 )
@@ -56,7 +57,15 @@ func (b *basicError) Render(status int, ctx contract.Context) error {
 		v[`stack`] = b.StackString()
 	}
 
-	return ctx.Render(status, v)
+	if err := ctx.Render(status, v); err != nil {
+		// The negotiated type such as xml can not encode the map, the error is answered with json instead
+		if p, ok := ctx.Protocol().(contract.HttpProtocol); ok {
+			p.ResponseWriter().Header().Del(`Content-Type`)
+		}
+		return ctx.RenderWith(status, render.JSON, v)
+	}
+
+	return nil
 }
 
 func (b *basicError) Stack() []uintptr {
@@ -99,6 +108,7 @@ func Error(message string) *basicError {
 	return &basicError{
 		message: message,
 		stack:   callers(),
+		meta:    make(map[string]interface{}, 0),
 	}
 }
 
@@ -106,6 +116,7 @@ func Errorf(format string, args ...interface{}) *basicError {
 	return &basicError{
 		message: fmt.Sprintf(format, args...),
 		stack:   callers(),
+		meta:    make(map[string]interface{}, 0),
 	}
 }
 
@@ -119,5 +130,6 @@ func ErrorWarp(err error) *basicError {
 	return &basicError{
 		stack: stacks,
 		err:   err,
+		meta:  make(map[string]interface{}, 0),
 	}
 }
@@ -0,0 +1,83 @@
+package render
+
+import (
+	"fmt"
+	"github.com/firmeve/firmeve/kernel/contract"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type (
+	file struct {
+	}
+
+	// The file downloaded with the filename of the Content-Disposition header
+	Attachment struct {
+		Path     string
+		Filename string
+	}
+)
+
+var (
+	File = file{}
+)
+
+// Render the file of the path or Attachment, a 200 status answers conditional and range requests
+func (file) Render(protocol contract.Protocol, status int, v interface{}) error {
+	var path, filename string
+	switch value := v.(type) {
+	case string:
+		path = value
+	case Attachment:
+		path, filename = value.Path, value.Filename
+	case *Attachment:
+		path, filename = value.Path, value.Filename
+	default:
+		return fmt.Errorf("value conversion failed %#v", v)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	} else if info.IsDir() {
+		return fmt.Errorf("%s is a directory", path)
+	}
+
+	p, ok := protocol.(contract.HttpProtocol)
+	if !ok {
+		_, err = io.Copy(protocol, f)
+		return err
+	}
+
+	if filename != `` {
+		p.ResponseWriter().Header().Set(`Content-Disposition`, ContentDisposition(`attachment`, filename))
+	}
+
+	return serveContent(p, status, filepath.Base(path), info.ModTime(), f)
+}
+
+// The Content-Disposition header value, non ASCII filenames are encoded as RFC 6266 filename*
+func ContentDisposition(disposition, filename string) string {
+	fallback := strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e || r == '"' || r == '\\' {
+			return '_'
+		}
+
+		return r
+	}, filename)
+
+	if fallback == filename {
+		return fmt.Sprintf(`%s; filename="%s"`, disposition, filename)
+	}
+
+	return fmt.Sprintf(`%s; filename="%s"; filename*=UTF-8''%s`, disposition, fallback, strings.Replace(url.QueryEscape(filename), `+`, `%20`, -1))
+}
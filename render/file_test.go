@@ -0,0 +1,118 @@
+package render
+
+import (
+	"github.com/firmeve/firmeve/kernel/contract"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type mockHttpProtocol struct {
+	contract.HttpProtocol
+	request        *http.Request
+	responseWriter http.ResponseWriter
+	pretty         bool
+}
+
+func (m *mockHttpProtocol) Request() *http.Request {
+	return m.request
+}
+
+func (m *mockHttpProtocol) ResponseWriter() http.ResponseWriter {
+	return m.responseWriter
+}
+
+func newMockHttpProtocol(header map[string]string) (*mockHttpProtocol, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, `/`, nil)
+	for key, value := range header {
+		req.Header.Set(key, value)
+	}
+
+	return &mockHttpProtocol{request: req, responseWriter: w}, w
+}
+
+func newTestingFile(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir(``, `firmeve`)
+	assert.Nil(t, err)
+	path := filepath.Join(dir, `report.txt`)
+	assert.Nil(t, ioutil.WriteFile(path, []byte(`0123456789`), 0644))
+
+	return path, func() {
+		os.RemoveAll(dir)
+	}
+}
+
+func TestFile_Render(t *testing.T) {
+	path, clean := newTestingFile(t)
+	defer clean()
+
+	protocol, w := newMockHttpProtocol(nil)
+	assert.Nil(t, File.Render(protocol, http.StatusOK, path))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `0123456789`, w.Body.String())
+	assert.Contains(t, w.Header().Get(`Content-Type`), `text/plain`)
+	assert.NotEmpty(t, w.Header().Get(`Last-Modified`))
+	assert.Empty(t, w.Header().Get(`Content-Disposition`))
+
+	protocol, w = newMockHttpProtocol(map[string]string{`Range`: `bytes=2-4`})
+	assert.Nil(t, File.Render(protocol, http.StatusOK, path))
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+	assert.Equal(t, `234`, w.Body.String())
+	assert.Equal(t, `bytes 2-4/10`, w.Header().Get(`Content-Range`))
+
+	protocol, w = newMockHttpProtocol(map[string]string{`Range`: `bytes=20-30`})
+	assert.Nil(t, File.Render(protocol, http.StatusOK, path))
+	assert.Equal(t, http.StatusRequestedRangeNotSatisfiable, w.Code)
+
+	protocol, w = newMockHttpProtocol(nil)
+	assert.Nil(t, File.Render(protocol, http.StatusAccepted, path))
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, `0123456789`, w.Body.String())
+
+	protocol, _ = newMockHttpProtocol(nil)
+	assert.NotNil(t, File.Render(protocol, http.StatusOK, filepath.Dir(path)))
+	assert.NotNil(t, File.Render(protocol, http.StatusOK, path+`.missing`))
+	assert.NotNil(t, File.Render(protocol, http.StatusOK, 1))
+}
+
+func TestFile_Render_Attachment(t *testing.T) {
+	path, clean := newTestingFile(t)
+	defer clean()
+
+	protocol, w := newMockHttpProtocol(nil)
+	assert.Nil(t, File.Render(protocol, http.StatusOK, Attachment{Path: path, Filename: `2020 report.txt`}))
+	assert.Equal(t, `attachment; filename="2020 report.txt"`, w.Header().Get(`Content-Disposition`))
+	assert.Equal(t, `0123456789`, w.Body.String())
+}
+
+func TestContentDisposition(t *testing.T) {
+	assert.Equal(t, `inline; filename="a.pdf"`, ContentDisposition(`inline`, `a.pdf`))
+	assert.Equal(t, `attachment; filename="_a_.txt"; filename*=UTF-8''%22a%22.txt`, ContentDisposition(`attachment`, `"a".txt`))
+	assert.Equal(t, `attachment; filename="__ 1.txt"; filename*=UTF-8''%E6%8A%A5%E5%91%8A%201.txt`, ContentDisposition(`attachment`, `报告 1.txt`))
+}
+
+func TestStream_Render(t *testing.T) {
+	protocol, w := newMockHttpProtocol(nil)
+	assert.Nil(t, Stream.Render(protocol, http.StatusOK, StreamReader{
+		Reader:      ioutil.NopCloser(strings.NewReader(strings.Repeat(`a`, streamChunkSize+10))),
+		ContentType: `text/csv`,
+	}))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `text/csv`, w.Header().Get(`Content-Type`))
+	assert.Equal(t, streamChunkSize+10, w.Body.Len())
+	assert.True(t, w.Flushed)
+
+	protocol, w = newMockHttpProtocol(map[string]string{`Range`: `bytes=0-2`})
+	assert.Nil(t, Stream.Render(protocol, http.StatusOK, StreamReader{Reader: strings.NewReader(`abcdef`)}))
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+	assert.Equal(t, `abc`, w.Body.String())
+
+	protocol, _ = newMockHttpProtocol(nil)
+	assert.NotNil(t, Stream.Render(protocol, http.StatusOK, `abc`))
+}
@@ -0,0 +1,46 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/firmeve/firmeve/kernel/contract"
+)
+
+type (
+	html struct {
+	}
+
+	// The template rendered by the view
+	HTMLTemplate struct {
+		View    contract.View
+		Context contract.Context
+		Name    string
+		Data    interface{}
+	}
+)
+
+var (
+	HTML = html{}
+)
+
+// The template is rendered into a buffer first, so that a failed template does not send a partial page
+func (html) Render(protocol contract.Protocol, status int, v interface{}) error {
+	var template HTMLTemplate
+	switch value := v.(type) {
+	case HTMLTemplate:
+		template = value
+	case *HTMLTemplate:
+		template = *value
+	default:
+		return fmt.Errorf("value conversion failed %#v", v)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := template.View.Render(template.Context, buf, template.Name, template.Data); err != nil {
+		return err
+	}
+
+	writeHeader(protocol, status, `text/html; charset=utf-8`)
+	_, err := protocol.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,43 @@
+package render
+
+import (
+	"fmt"
+	"github.com/firmeve/firmeve/kernel/contract"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"testing"
+)
+
+type mockView struct {
+}
+
+func (mockView) Render(c contract.Context, w io.Writer, name string, data interface{}) error {
+	if name == `missing` {
+		return fmt.Errorf("the view %s does not exist", name)
+	}
+
+	_, err := fmt.Fprintf(w, "<p>%s %v</p>", name, data)
+	return err
+}
+
+func TestHTML_Render(t *testing.T) {
+	protocol, w := newMockHttpProtocol(nil)
+	assert.Nil(t, HTML.Render(protocol, http.StatusCreated, HTMLTemplate{View: mockView{}, Name: `index`, Data: 1}))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, `text/html; charset=utf-8`, w.Header().Get(`Content-Type`))
+	assert.Equal(t, `<p>index 1</p>`, w.Body.String())
+
+	protocol, w = newMockHttpProtocol(nil)
+	assert.NotNil(t, HTML.Render(protocol, http.StatusOK, &HTMLTemplate{View: mockView{}, Name: `missing`}))
+	assert.Equal(t, 0, w.Body.Len())
+	assert.NotNil(t, HTML.Render(protocol, http.StatusOK, `index`))
+
+	protocol, w = newMockHttpProtocol(map[string]string{`Accept`: `text/html,application/xml;q=0.9`})
+	assert.Nil(t, RenderOffers(protocol, http.StatusOK, map[string]interface{}{
+		contract.HttpMimeHtml: HTMLTemplate{View: mockView{}, Name: `index`, Data: 1},
+		contract.HttpMimeJson: 1,
+	}))
+	assert.Equal(t, `text/html; charset=utf-8`, w.Header().Get(`Content-Type`))
+	assert.Equal(t, `<p>index 1</p>`, w.Body.String())
+}
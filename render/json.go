@@ -1,29 +1,173 @@
 package render
 
 import (
+	"bytes"
 	json2 "encoding/json"
+	"fmt"
 	"github.com/firmeve/firmeve/kernel/contract"
+	"github.com/firmeve/firmeve/support"
+	"io"
+	"regexp"
 )
 
 type (
 	json struct {
+		option *jsonOption
+	}
+
+	jsonOption struct {
+		pretty      bool
+		prettyQuery string
+		prefix      string
+		indent      string
+		escapeHTML  bool
+		callback    string
+		stream      bool
+	}
+
+	// Http protocols indenting every json response, such as in development mode
+	prettyProtocol interface {
+		PrettyJSON() bool
 	}
 )
 
 var (
-	JSON = json{}
+	JSON = json{
+		option: &jsonOption{
+			prettyQuery: `pretty`,
+			indent:      `  `,
+			escapeHTML:  true,
+		},
+	}
+	// Identifiers and dotted paths such as jQuery.callbacks.cb_1
+	jsonpCallback = regexp.MustCompile(`^[a-zA-Z_$][0-9a-zA-Z_$]*(\.[a-zA-Z_$][0-9a-zA-Z_$]*)*$`)
 )
 
-func (json) Render(protocol contract.Protocol, status int, v interface{}) error {
+// Indent every response, such as in development mode
+func JSONPretty(pretty bool) support.Option {
+	return func(object support.Object) {
+		object.(*jsonOption).pretty = pretty
+	}
+}
+
+// Indent the responses of the http requests with the query, the default is ?pretty, empty disables the query
+func JSONPrettyQuery(query string) support.Option {
+	return func(object support.Object) {
+		object.(*jsonOption).prettyQuery = query
+	}
+}
+
+func JSONIndent(prefix, indent string) support.Option {
+	return func(object support.Object) {
+		object.(*jsonOption).prefix = prefix
+		object.(*jsonOption).indent = indent
+	}
+}
+
+// Escape <, > and & of the strings, the default is true
+func JSONEscapeHTML(escapeHTML bool) support.Option {
+	return func(object support.Object) {
+		object.(*jsonOption).escapeHTML = escapeHTML
+	}
+}
+
+// Wrap the http responses in the function named by the query, e.g. JSONP(`callback`) for ?callback=cb
+func JSONP(query string) support.Option {
+	return func(object support.Object) {
+		object.(*jsonOption).callback = query
+	}
+}
+
+// Encode to the response with json.Encoder instead of a marshalled copy of the value,
+// the status is written before encoding, so an encoding error can no longer change it
+func JSONStream(stream bool) support.Option {
+	return func(object support.Object) {
+		object.(*jsonOption).stream = stream
+	}
+}
+
+// A copy of the render with the options, e.g. RenderWith(200, render.JSON.With(render.JSONStream(true)), rows)
+func (j json) With(options ...support.Option) json {
+	option := *j.option
+	return json{option: support.ApplyOption(&option, options...).(*jsonOption)}
+}
+
+func (j json) Render(protocol contract.Protocol, status int, v interface{}) error {
+	option := j.option
+	if option == nil {
+		option = JSON.option
+	}
+
+	pretty, callback := option.pretty, ``
 	if p, ok := protocol.(contract.HttpProtocol); ok {
-		p.ResponseWriter().WriteHeader(status)
-		p.SetHeader(`Content-Type`, `application/json`)
+		if pp, ok := p.(prettyProtocol); ok && pp.PrettyJSON() {
+			pretty = true
+		}
+		query := p.Request().URL.Query()
+		if option.prettyQuery != `` {
+			if _, ok := query[option.prettyQuery]; ok {
+				pretty = true
+			}
+		}
+		if option.callback != `` {
+			callback = query.Get(option.callback)
+			if callback != `` && !jsonpCallback.MatchString(callback) {
+				return fmt.Errorf("invalid jsonp callback %s", callback)
+			}
+		}
+	}
+
+	if option.stream {
+		j.writeHeader(protocol, status, callback)
+		return j.encode(protocol, option, pretty, callback, v)
 	}
 
-	bytes, err := json2.Marshal(v)
-	if err != nil {
+	buf := new(bytes.Buffer)
+	if err := j.encode(buf, option, pretty, callback, v); err != nil {
 		return err
 	}
-	_, err = protocol.Write(bytes)
+	j.writeHeader(protocol, status, callback)
+	_, err := protocol.Write(buf.Bytes())
 	return err
 }
+
+func (json) writeHeader(protocol contract.Protocol, status int, callback string) {
+	if callback == `` {
+		writeHeader(protocol, status, `application/json`)
+		return
+	}
+
+	p := protocol.(contract.HttpProtocol)
+	p.ResponseWriter().Header().Set(`Content-Type`, `application/javascript`)
+	p.ResponseWriter().Header().Set(`X-Content-Type-Options`, `nosniff`)
+	p.ResponseWriter().WriteHeader(status)
+}
+
+// The encoder appends a newline, which is trimmed from the buffered value
+func (json) encode(w io.Writer, option *jsonOption, pretty bool, callback string, v interface{}) error {
+	if callback != `` {
+		// The comment keeps the response from being sniffed as a flash file
+		if _, err := w.Write([]byte(`/**/` + callback + `(`)); err != nil {
+			return err
+		}
+	}
+
+	encoder := json2.NewEncoder(w)
+	encoder.SetEscapeHTML(option.escapeHTML)
+	if pretty {
+		encoder.SetIndent(option.prefix, option.indent)
+	}
+	if err := encoder.Encode(v); err != nil {
+		return err
+	}
+	if buf, ok := w.(*bytes.Buffer); ok {
+		buf.Truncate(buf.Len() - 1)
+	}
+
+	if callback != `` {
+		_, err := w.Write([]byte(`);`))
+		return err
+	}
+
+	return nil
+}
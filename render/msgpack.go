@@ -0,0 +1,26 @@
+package render
+
+import (
+	"github.com/firmeve/firmeve/kernel/contract"
+	msgpack2 "github.com/vmihailenco/msgpack/v4"
+)
+
+type (
+	msgPack struct {
+	}
+)
+
+var (
+	MsgPack = msgPack{}
+)
+
+func (msgPack) Render(protocol contract.Protocol, status int, v interface{}) error {
+	// A failed marshal leaves the status unwritten, so that the error can still be answered
+	bytes, err := msgpack2.Marshal(v)
+	if err != nil {
+		return err
+	}
+	writeHeader(protocol, status, `application/x-msgpack`)
+	_, err = protocol.Write(bytes)
+	return err
+}
@@ -0,0 +1,119 @@
+package render
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type (
+	mediaRange struct {
+		typ     string
+		subtype string
+		params  map[string]string
+		q       float64
+	}
+)
+
+// Parse the media ranges of the Accept header, ordered by the quality and the specificity.
+// Invalid ranges are skipped, e.g. "text/html, application/json;q=0.9, */*;q=0.1"
+func parseAccept(accept string) []mediaRange {
+	ranges := make([]mediaRange, 0)
+	for _, item := range strings.Split(accept, `,`) {
+		parts := strings.Split(item, `;`)
+		typ := strings.Split(strings.ToLower(strings.TrimSpace(parts[0])), `/`)
+		if len(typ) != 2 || typ[0] == `` || typ[1] == `` || (typ[0] == `*` && typ[1] != `*`) {
+			continue
+		}
+
+		r := mediaRange{typ: typ[0], subtype: typ[1], params: make(map[string]string, 0), q: 1}
+		for _, param := range parts[1:] {
+			kv := strings.SplitN(strings.TrimSpace(param), `=`, 2)
+			if len(kv) != 2 {
+				continue
+			}
+			key, value := strings.ToLower(strings.TrimSpace(kv[0])), strings.Trim(strings.TrimSpace(kv[1]), `"`)
+			if key == `q` {
+				if q, err := strconv.ParseFloat(value, 64); err == nil && q >= 0 && q <= 1 {
+					r.q = q
+				}
+			} else {
+				r.params[key] = value
+			}
+		}
+		ranges = append(ranges, r)
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		if ranges[i].q != ranges[j].q {
+			return ranges[i].q > ranges[j].q
+		}
+		return ranges[i].specificity() > ranges[j].specificity()
+	})
+
+	return ranges
+}
+
+// The accepted media types ordered by preference, the ranges of quality 0 are excluded
+func AcceptedTypes(accept string) []string {
+	ranges := parseAccept(accept)
+	types := make([]string, 0, len(ranges))
+	for _, r := range ranges {
+		if r.q > 0 {
+			types = append(types, r.typ+`/`+r.subtype)
+		}
+	}
+
+	return types
+}
+
+// Select the offer preferred by the Accept header following RFC 7231, the quality of an offer comes from
+// the most specific matching range. Offers of equal quality keep the order of the server preference,
+// a missing Accept header accepts the first offer and an empty string means no offer is acceptable
+func Negotiate(accept string, offers ...string) string {
+	if len(offers) == 0 {
+		return ``
+	} else if strings.TrimSpace(accept) == `` {
+		return offers[0]
+	}
+
+	ranges := parseAccept(accept)
+	best, bestQ := ``, 0.0
+	for _, offer := range offers {
+		typ := strings.Split(strings.ToLower(strings.Split(offer, `;`)[0]), `/`)
+		if len(typ) != 2 {
+			continue
+		}
+
+		q, specificity := 0.0, -1
+		for _, r := range ranges {
+			if s := r.match(typ[0], typ[1]); s > specificity {
+				q, specificity = r.q, s
+			}
+		}
+		if q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+
+	return best
+}
+
+// The specificity of the matching range, -1 when the range does not match
+func (r mediaRange) match(typ, subtype string) int {
+	if r.typ == `*` {
+		return 0
+	} else if r.typ != typ {
+		return -1
+	} else if r.subtype == `*` {
+		return 1
+	} else if r.subtype != subtype {
+		return -1
+	}
+
+	return 2 + len(r.params)
+}
+
+func (r mediaRange) specificity() int {
+	return r.match(r.typ, r.subtype)
+}
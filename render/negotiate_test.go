@@ -0,0 +1,29 @@
+package render
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	offers := []string{`application/json`, `text/html`, `text/plain`}
+	assert.Equal(t, `application/json`, Negotiate(``, offers...))
+	assert.Equal(t, `application/json`, Negotiate(`*/*`, offers...))
+	assert.Equal(t, `text/html`, Negotiate(`text/*, application/json;q=0.5`, offers...))
+	assert.Equal(t, `text/plain`, Negotiate(`text/*;q=0.5, text/plain`, offers...))
+	assert.Equal(t, `application/json`, Negotiate(`text/*;q=0.5, text/html;q=0, */*;q=0.8`, offers...))
+	assert.Equal(t, `text/plain`, Negotiate(`TEXT/PLAIN`, offers...))
+	assert.Equal(t, ``, Negotiate(`image/png`, offers...))
+	assert.Equal(t, ``, Negotiate(`*/*;q=0`, offers...))
+	assert.Equal(t, ``, Negotiate(`*/*`))
+	// invalid ranges are skipped
+	assert.Equal(t, `text/html`, Negotiate(`*/json, text, text/html`, offers...))
+}
+
+func TestAcceptedTypes(t *testing.T) {
+	assert.Equal(t, []string{`text/html`, `application/xhtml+xml`, `application/xml`, `*/*`},
+		AcceptedTypes(`text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8`))
+	assert.Equal(t, []string{`text/html`, `text/*`, `*/*`}, AcceptedTypes(`*/*, text/*, text/html`))
+	assert.Equal(t, []string{`application/json`}, AcceptedTypes(`image/png;q=0, application/json; q="0.5"`))
+	assert.Equal(t, []string{}, AcceptedTypes(``))
+}
@@ -15,10 +15,7 @@ var (
 )
 
 func (plain) Render(protocol contract.Protocol, status int, v interface{}) error {
-	if p, ok := protocol.(contract.HttpProtocol); ok {
-		p.ResponseWriter().WriteHeader(status)
-		p.SetHeader(`Content-Type`, `text/plain`)
-	}
+	writeHeader(protocol, status, `text/plain`)
 
 	if bytes, ok := v.([]byte); ok {
 		_, err := protocol.Write(bytes)
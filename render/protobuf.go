@@ -0,0 +1,33 @@
+package render
+
+import (
+	"fmt"
+	"github.com/firmeve/firmeve/kernel/contract"
+	"github.com/golang/protobuf/proto"
+)
+
+type (
+	protobuf struct {
+	}
+)
+
+var (
+	Protobuf = protobuf{}
+)
+
+// The value must be a generated proto.Message
+func (protobuf) Render(protocol contract.Protocol, status int, v interface{}) error {
+	message, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("value conversion failed %#v", v)
+	}
+
+	bytes, err := proto.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	writeHeader(protocol, status, `application/x-protobuf`)
+	_, err = protocol.Write(bytes)
+	return err
+}
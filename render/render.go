@@ -3,27 +3,138 @@ package render
 import (
 	"fmt"
 	"github.com/firmeve/firmeve/kernel/contract"
+	"sort"
 )
 
 var (
 	httpRenderType = map[string]contract.Render{
-		contract.HttpMimeJson:  JSON,
-		contract.HttpMimePlain: Plain,
+		contract.HttpMimeJson:     JSON,
+		contract.HttpMimePlain:    Plain,
+		contract.HttpMimeXml:      XML,
+		contract.HttpMimeXml2:     XML,
+		contract.HttpMimeYaml:     YAML,
+		contract.HttpMimeYaml2:    YAML,
+		contract.HttpMimeMsgPack:  MsgPack,
+		contract.HttpMimeMsgPack2: MsgPack,
+		contract.HttpMimeProtobuf: Protobuf,
 	}
+	// The negotiation order of the types of equal quality
+	httpRenderTypes = []string{
+		contract.HttpMimeJson, contract.HttpMimePlain, contract.HttpMimeXml, contract.HttpMimeXml2,
+		contract.HttpMimeYaml, contract.HttpMimeYaml2, contract.HttpMimeMsgPack, contract.HttpMimeMsgPack2,
+		contract.HttpMimeProtobuf,
+	}
+	defaultType = contract.HttpMimeJson
 )
 
+// Register the render of the accepted type, the registered types are replaced
+func Register(accept string, render contract.Render) {
+	if _, ok := httpRenderType[accept]; !ok {
+		httpRenderTypes = append(httpRenderTypes, accept)
+	}
+	httpRenderType[accept] = render
+}
+
+// The type rendered for a missing or unacceptable Accept header and for the protocols without negotiation
+func SetDefault(accept string) {
+	if _, ok := httpRenderType[accept]; !ok {
+		panic(fmt.Errorf("non-existent type %s", accept))
+	}
+	defaultType = accept
+}
+
 func Render(protocol contract.Protocol, status int, v interface{}) error {
+	// Protocols rendering the value themselves, such as grpc
+	if r, ok := protocol.(contract.Render); ok {
+		return r.Render(protocol, status, v)
+	}
+
+	accept := defaultType
 	if p, ok := protocol.(contract.HttpProtocol); ok {
-		accept := p.Accept()
+		accept = negotiate(p, sortOffers(httpRenderTypes))
+		p.ResponseWriter().Header().Set(`Content-Type`, accept)
+	}
 
-		for _, item := range accept {
-			if r, ok := httpRenderType[item]; ok {
-				return r.Render(protocol, status, v)
-			}
-		}
+	return httpRenderType[accept].Render(protocol, status, v)
+}
 
-		return fmt.Errorf("non-existent type %v", accept)
+// Render the value of the type preferred by the Accept header, e.g. map[string]interface{}{"application/json": user, "text/plain": []byte(user.Name)}
+func RenderOffers(protocol contract.Protocol, status int, values map[string]interface{}) error {
+	if len(values) == 0 {
+		return fmt.Errorf("no value is offered")
 	}
 
-	return nil
+	offers := make([]string, 0, len(values))
+	for offer := range values {
+		offers = append(offers, offer)
+	}
+	offers = sortOffers(offers)
+
+	p, ok := protocol.(contract.HttpProtocol)
+	if !ok {
+		return Render(protocol, status, values[offers[0]])
+	}
+
+	accept := negotiate(p, offers)
+	r, ok := httpRenderType[accept]
+	// HTML is offered explicitly only, the values of Render are not templates
+	if accept == contract.HttpMimeHtml {
+		r, ok = HTML, true
+	}
+	if !ok {
+		return fmt.Errorf("non-existent type %s", accept)
+	}
+	if accept != contract.HttpMimeHtml {
+		p.ResponseWriter().Header().Set(`Content-Type`, accept)
+	}
+
+	return r.Render(protocol, status, values[accept])
+}
+
+// Unacceptable requests are served with the first offer instead of 406 Not Acceptable, as RFC 7231 allows
+func negotiate(p contract.HttpProtocol, offers []string) string {
+	p.ResponseWriter().Header().Add(`Vary`, `Accept`)
+	if accept := Negotiate(p.Header(`Accept`), offers...); accept != `` {
+		return accept
+	}
+
+	return offers[0]
+}
+
+// The default type comes first, the others keep the registration order and the unregistered types come last
+func sortOffers(offers []string) []string {
+	order := make(map[string]int, len(httpRenderTypes))
+	for i, v := range httpRenderTypes {
+		order[v] = i + 1
+	}
+	order[defaultType] = 0
+
+	sorted := append(make([]string, 0, len(offers)), offers...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		oi, ok := order[sorted[i]]
+		if !ok {
+			oi = len(order) + 1
+		}
+		oj, ok := order[sorted[j]]
+		if !ok {
+			oj = len(order) + 1
+		}
+		if oi != oj {
+			return oi < oj
+		}
+		return sorted[i] < sorted[j]
+	})
+
+	return sorted
+}
+
+// Set the content type before writing the status, headers set after WriteHeader are ignored.
+// The negotiated type, such as text/xml for the XML render, takes precedence
+func writeHeader(protocol contract.Protocol, status int, contentType string) {
+	if p, ok := protocol.(contract.HttpProtocol); ok {
+		if p.ResponseWriter().Header().Get(`Content-Type`) == `` {
+			p.ResponseWriter().Header().Set(`Content-Type`, contentType)
+		}
+		p.ResponseWriter().WriteHeader(status)
+	}
 }
@@ -0,0 +1,180 @@
+package render
+
+import (
+	"github.com/firmeve/firmeve/kernel/contract"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/stretchr/testify/assert"
+	msgpack2 "github.com/vmihailenco/msgpack/v4"
+	"net/http"
+	"testing"
+)
+
+type mockUser struct {
+	ID   int    `json:"id" xml:"id" yaml:"id" msgpack:"id"`
+	Name string `json:"name" xml:"name" yaml:"name" msgpack:"name"`
+}
+
+func (m *mockHttpProtocol) PrettyJSON() bool {
+	return m.pretty
+}
+
+func (m *mockHttpProtocol) Write(p []byte) (int, error) {
+	return m.responseWriter.Write(p)
+}
+
+func (m *mockHttpProtocol) Header(key string) string {
+	return m.request.Header.Get(key)
+}
+
+func TestRender(t *testing.T) {
+	user := mockUser{ID: 1, Name: `simon`}
+	for accept, body := range map[string]string{
+		contract.HttpMimeJson:  `{"id":1,"name":"simon"}`,
+		contract.HttpMimeXml:   `<mockUser><id>1</id><name>simon</name></mockUser>`,
+		contract.HttpMimeXml2:  `<mockUser><id>1</id><name>simon</name></mockUser>`,
+		contract.HttpMimeYaml:  "id: 1\nname: simon\n",
+		contract.HttpMimeYaml2: "id: 1\nname: simon\n",
+	} {
+		protocol, w := newMockHttpProtocol(map[string]string{`Accept`: accept})
+		assert.Nil(t, Render(protocol, http.StatusCreated, user))
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, body, w.Body.String())
+		assert.NotEmpty(t, w.Header().Get(`Content-Type`))
+	}
+
+	protocol, w := newMockHttpProtocol(map[string]string{`Accept`: contract.HttpMimeMsgPack})
+	assert.Nil(t, Render(protocol, http.StatusOK, user))
+	assert.Equal(t, `application/x-msgpack`, w.Header().Get(`Content-Type`))
+	decoded := mockUser{}
+	assert.Nil(t, msgpack2.Unmarshal(w.Body.Bytes(), &decoded))
+	assert.Equal(t, user, decoded)
+
+	protocol, w = newMockHttpProtocol(map[string]string{`Accept`: contract.HttpMimeProtobuf})
+	assert.Nil(t, Render(protocol, http.StatusOK, &wrappers.StringValue{Value: `simon`}))
+	assert.Equal(t, `application/x-protobuf`, w.Header().Get(`Content-Type`))
+	message := new(wrappers.StringValue)
+	assert.Nil(t, proto.Unmarshal(w.Body.Bytes(), message))
+	assert.Equal(t, `simon`, message.Value)
+
+	protocol, _ = newMockHttpProtocol(map[string]string{`Accept`: contract.HttpMimeProtobuf})
+	assert.NotNil(t, Render(protocol, http.StatusOK, user))
+}
+
+func TestRegister(t *testing.T) {
+	Register(`application/vnd.firmeve+json`, JSON)
+	defer delete(httpRenderType, `application/vnd.firmeve+json`)
+
+	protocol, w := newMockHttpProtocol(map[string]string{`Accept`: `application/vnd.firmeve+json`})
+	assert.Nil(t, Render(protocol, http.StatusOK, mockUser{ID: 1}))
+	assert.Equal(t, `{"id":1,"name":""}`, w.Body.String())
+}
+
+func TestRender_Negotiation(t *testing.T) {
+	user := mockUser{ID: 1, Name: `simon`}
+	for accept, contentType := range map[string]string{
+		``:                                  `application/json`,
+		`*/*`:                               `application/json`,
+		`application/json; q=0.9`:           `application/json`,
+		`application/*`:                     `application/json`,
+		`image/png`:                         `application/json`,
+		`application/json;q=0.5, text/xml`:  `text/xml`,
+		`text/html, application/xml;q=0.9`:  `application/xml`,
+		`application/*;q=0.5, */*;q=0.1`:    `application/json`,
+		`application/x-yaml, application/*`: `application/json`,
+		`application/x-yaml, application/json;q=0`: `application/x-yaml`,
+	} {
+		protocol, w := newMockHttpProtocol(map[string]string{`Accept`: accept})
+		assert.Nil(t, Render(protocol, http.StatusOK, user), accept)
+		assert.Equal(t, contentType, w.Header().Get(`Content-Type`), accept)
+		assert.Equal(t, `Accept`, w.Header().Get(`Vary`))
+	}
+
+	SetDefault(contract.HttpMimeYaml)
+	defer SetDefault(contract.HttpMimeJson)
+	protocol, w := newMockHttpProtocol(nil)
+	assert.Nil(t, Render(protocol, http.StatusOK, user))
+	assert.Equal(t, `application/x-yaml`, w.Header().Get(`Content-Type`))
+	assert.Panics(t, func() {
+		SetDefault(`image/png`)
+	})
+}
+
+func TestRenderOffers(t *testing.T) {
+	values := map[string]interface{}{
+		contract.HttpMimeJson:  mockUser{ID: 1, Name: `simon`},
+		contract.HttpMimePlain: []byte(`simon`),
+	}
+
+	protocol, w := newMockHttpProtocol(map[string]string{`Accept`: `text/*`})
+	assert.Nil(t, RenderOffers(protocol, http.StatusOK, values))
+	assert.Equal(t, `simon`, w.Body.String())
+
+	protocol, w = newMockHttpProtocol(map[string]string{`Accept`: `*/*`})
+	assert.Nil(t, RenderOffers(protocol, http.StatusOK, values))
+	assert.Equal(t, `{"id":1,"name":"simon"}`, w.Body.String())
+
+	protocol, _ = newMockHttpProtocol(map[string]string{`Accept`: `text/csv`})
+	assert.NotNil(t, RenderOffers(protocol, http.StatusOK, map[string]interface{}{`text/csv`: `id`}))
+	assert.NotNil(t, RenderOffers(protocol, http.StatusOK, nil))
+}
+
+func TestRender_MarshalError(t *testing.T) {
+	for _, r := range []contract.Render{XML, YAML, MsgPack} {
+		protocol, w := newMockHttpProtocol(nil)
+		assert.NotNil(t, r.Render(protocol, http.StatusOK, func() {}))
+		assert.Equal(t, ``, w.Header().Get(`Content-Type`))
+		assert.Equal(t, 0, w.Body.Len())
+
+		// the status is still unwritten
+		protocol.ResponseWriter().WriteHeader(http.StatusInternalServerError)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestJSON_Render(t *testing.T) {
+	user := map[string]interface{}{`id`: 1, `name`: `<simon>`}
+
+	protocol, w := newMockHttpProtocol(nil)
+	assert.Nil(t, JSON.Render(protocol, http.StatusOK, user))
+	assert.Equal(t, `{"id":1,"name":"\u003csimon\u003e"}`, w.Body.String())
+
+	protocol, w = newMockHttpProtocol(nil)
+	protocol.request.URL.RawQuery = `pretty`
+	assert.Nil(t, JSON.With(JSONEscapeHTML(false)).Render(protocol, http.StatusOK, user))
+	assert.Equal(t, "{\n  \"id\": 1,\n  \"name\": \"<simon>\"\n}", w.Body.String())
+
+	protocol, w = newMockHttpProtocol(nil)
+	assert.Nil(t, JSON.With(JSONPretty(true), JSONIndent(``, "\t"), JSONStream(true)).Render(protocol, http.StatusCreated, []int{1}))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "[\n\t1\n]\n", w.Body.String())
+
+	jsonp := JSON.With(JSONP(`callback`))
+	protocol, w = newMockHttpProtocol(nil)
+	protocol.request.URL.RawQuery = `callback=jQuery.cb_1`
+	assert.Nil(t, jsonp.Render(protocol, http.StatusOK, []int{1}))
+	assert.Equal(t, `/**/jQuery.cb_1([1]);`, w.Body.String())
+	assert.Equal(t, `application/javascript`, w.Header().Get(`Content-Type`))
+	assert.Equal(t, `nosniff`, w.Header().Get(`X-Content-Type-Options`))
+
+	protocol, w = newMockHttpProtocol(nil)
+	protocol.request.URL.RawQuery = `callback=alert(1)//`
+	assert.NotNil(t, jsonp.Render(protocol, http.StatusOK, []int{1}))
+	assert.Equal(t, 0, w.Body.Len())
+
+	protocol, w = newMockHttpProtocol(nil)
+	assert.NotNil(t, JSON.Render(protocol, http.StatusOK, func() {}))
+	assert.Equal(t, 0, w.Body.Len())
+
+	// the copies do not change the options of JSON
+	protocol, w = newMockHttpProtocol(nil)
+	protocol.request.URL.RawQuery = `callback=cb`
+	assert.Nil(t, JSON.Render(protocol, http.StatusOK, 1))
+	assert.Equal(t, `1`, w.Body.String())
+
+	// the protocols indenting every response
+	protocol, w = newMockHttpProtocol(map[string]string{`Accept`: `application/json`})
+	protocol.pretty = true
+	assert.Nil(t, Render(protocol, http.StatusOK, []int{1}))
+	assert.Equal(t, "[\n  1\n]", w.Body.String())
+}
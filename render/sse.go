@@ -0,0 +1,157 @@
+package render
+
+import (
+	"context"
+	"fmt"
+	"github.com/firmeve/firmeve/kernel/contract"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	sse struct {
+	}
+
+	// Emit the events of the stream until the handler returns
+	SSEHandler func(emit func(event, data string)) error
+
+	// The event stream, the handler should return once the context is done
+	SSEStream struct {
+		Context context.Context
+		Handler SSEHandler
+		// The interval of the heartbeat comments keeping the connection alive, the default is 15 seconds
+		Heartbeat time.Duration
+	}
+
+	sseWriter struct {
+		w       http.ResponseWriter
+		flusher http.Flusher
+		ctx     context.Context
+		id      int64
+		mutex   sync.Mutex
+	}
+)
+
+const (
+	sseHeartbeat = 15 * time.Second
+)
+
+var (
+	SSE = sse{}
+
+	sseLineBreaks = strings.NewReplacer("\r", ``, "\n", ``)
+	sseNewLines   = strings.NewReplacer("\r\n", "\n", "\r", "\n")
+)
+
+// Render the SSEStream as text/event-stream, every event is flushed as it is emitted.
+// Event ids continue after the Last-Event-ID header of the reconnecting client
+func (sse) Render(protocol contract.Protocol, status int, v interface{}) error {
+	var stream SSEStream
+	switch value := v.(type) {
+	case SSEStream:
+		stream = value
+	case *SSEStream:
+		stream = *value
+	default:
+		return fmt.Errorf("value conversion failed %#v", v)
+	}
+
+	p, ok := protocol.(contract.HttpProtocol)
+	if !ok {
+		return fmt.Errorf("server-sent events require the http protocol")
+	}
+
+	flusher, ok := p.ResponseWriter().(http.Flusher)
+	if !ok {
+		return fmt.Errorf("the response writer does not support flushing")
+	}
+
+	if stream.Context == nil {
+		stream.Context = p.Request().Context()
+	}
+	if stream.Heartbeat <= 0 {
+		stream.Heartbeat = sseHeartbeat
+	}
+
+	writer := &sseWriter{
+		w:       p.ResponseWriter(),
+		flusher: flusher,
+		ctx:     stream.Context,
+	}
+	writer.id, _ = strconv.ParseInt(p.Request().Header.Get(`Last-Event-ID`), 10, 64)
+
+	header := p.ResponseWriter().Header()
+	header.Set(`Content-Type`, `text/event-stream`)
+	header.Set(`Cache-Control`, `no-cache`)
+	header.Set(`Connection`, `keep-alive`)
+	// Disable the response buffering of nginx
+	header.Set(`X-Accel-Buffering`, `no`)
+	p.ResponseWriter().WriteHeader(status)
+	flusher.Flush()
+
+	// Wait for the heartbeat to stop, the response writer must not be used after the request is served
+	done, stopped := make(chan struct{}), make(chan struct{})
+	go func() {
+		writer.heartbeat(stream.Heartbeat, done)
+		close(stopped)
+	}()
+	defer func() {
+		close(done)
+		<-stopped
+	}()
+
+	return stream.Handler(writer.emit)
+}
+
+func (s *sseWriter) emit(event, data string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	// Events emitted after the client disconnected are dropped
+	if s.ctx.Err() != nil {
+		return
+	}
+
+	// The ids are numbers, while the line breaks of the event would start new fields
+	s.id++
+	event = sseLineBreaks.Replace(event)
+	message := new(strings.Builder)
+	message.WriteString(`id: ` + strconv.FormatInt(s.id, 10) + "\n")
+	if event != `` {
+		message.WriteString(`event: ` + event + "\n")
+	}
+	// CR, LF and CRLF all end the lines of the stream
+	data = sseNewLines.Replace(data)
+	for _, line := range strings.Split(data, "\n") {
+		message.WriteString(`data: ` + line + "\n")
+	}
+	message.WriteString("\n")
+
+	s.write(message.String())
+}
+
+func (s *sseWriter) heartbeat(interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			s.mutex.Lock()
+			s.write(": heartbeat\n\n")
+			s.mutex.Unlock()
+		}
+	}
+}
+
+func (s *sseWriter) write(message string) {
+	if _, err := s.w.Write([]byte(message)); err == nil {
+		s.flusher.Flush()
+	}
+}
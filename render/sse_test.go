@@ -0,0 +1,68 @@
+package render
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSSE_Render(t *testing.T) {
+	protocol, w := newMockHttpProtocol(map[string]string{`Last-Event-ID`: `5`})
+	err := SSE.Render(protocol, http.StatusOK, SSEStream{
+		Handler: func(emit func(event, data string)) error {
+			emit(`update`, `{"cpu":1}`)
+			emit(``, "line1\nline2")
+			return nil
+		},
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `text/event-stream`, w.Header().Get(`Content-Type`))
+	assert.Equal(t, `no-cache`, w.Header().Get(`Cache-Control`))
+	assert.True(t, w.Flushed)
+	assert.Equal(t, "id: 6\nevent: update\ndata: {\"cpu\":1}\n\nid: 7\ndata: line1\ndata: line2\n\n", w.Body.String())
+}
+
+func TestSSE_Render_LineBreaks(t *testing.T) {
+	protocol, w := newMockHttpProtocol(nil)
+	err := SSE.Render(protocol, http.StatusOK, SSEStream{
+		Handler: func(emit func(event, data string)) error {
+			emit("update\ndata: injected\r\nid: 100", "line1\rid: 100")
+			return nil
+		},
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "id: 1\nevent: updatedata: injectedid: 100\ndata: line1\ndata: id: 100\n\n", w.Body.String())
+}
+
+func TestSSE_Render_Heartbeat(t *testing.T) {
+	protocol, w := newMockHttpProtocol(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	protocol.request = protocol.request.WithContext(ctx)
+
+	err := SSE.Render(protocol, http.StatusOK, SSEStream{
+		Heartbeat: 10 * time.Millisecond,
+		Handler: func(emit func(event, data string)) error {
+			emit(`ping`, `1`)
+			time.Sleep(35 * time.Millisecond)
+			cancel()
+			emit(`ping`, `2`)
+			return nil
+		},
+	})
+
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(w.Body.String(), "id: 1\nevent: ping\ndata: 1\n\n: heartbeat\n\n"))
+	assert.NotContains(t, w.Body.String(), `data: 2`)
+}
+
+func TestSSE_Render_Error(t *testing.T) {
+	protocol, _ := newMockHttpProtocol(nil)
+	assert.NotNil(t, SSE.Render(protocol, http.StatusOK, `event`))
+	assert.NotNil(t, SSE.Render(nil, http.StatusOK, SSEStream{}))
+}
@@ -0,0 +1,115 @@
+package render
+
+import (
+	"fmt"
+	"github.com/firmeve/firmeve/kernel/contract"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"time"
+)
+
+type (
+	stream struct {
+	}
+
+	// The reader streamed with the content type, an io.ReadSeeker answers range requests
+	StreamReader struct {
+		Reader      io.Reader
+		ContentType string
+	}
+)
+
+const (
+	streamChunkSize = 32 * 1024
+)
+
+var (
+	Stream = stream{}
+)
+
+// Render the StreamReader, chunks are flushed to the client as they are read
+func (stream) Render(protocol contract.Protocol, status int, v interface{}) error {
+	var reader StreamReader
+	switch value := v.(type) {
+	case StreamReader:
+		reader = value
+	case *StreamReader:
+		reader = *value
+	case io.Reader:
+		reader = StreamReader{Reader: value}
+	default:
+		return fmt.Errorf("value conversion failed %#v", v)
+	}
+
+	p, ok := protocol.(contract.HttpProtocol)
+	if !ok {
+		_, err := io.Copy(protocol, reader.Reader)
+		return err
+	}
+
+	if reader.ContentType != `` {
+		p.ResponseWriter().Header().Set(`Content-Type`, reader.ContentType)
+	}
+
+	if seeker, ok := reader.Reader.(io.ReadSeeker); ok {
+		return serveContent(p, status, ``, time.Time{}, seeker)
+	}
+
+	return flush(p, status, reader.Reader)
+}
+
+// Serve the content with range and conditional request support, other statuses than 200 write the whole content
+func serveContent(p contract.HttpProtocol, status int, name string, modTime time.Time, content io.ReadSeeker) error {
+	w := p.ResponseWriter()
+	if status == 0 || status == http.StatusOK {
+		http.ServeContent(w, p.Request(), name, modTime, content)
+		return nil
+	}
+
+	if w.Header().Get(`Content-Type`) == `` {
+		if contentType := mime.TypeByExtension(filepath.Ext(name)); contentType != `` {
+			w.Header().Set(`Content-Type`, contentType)
+		}
+	}
+	w.WriteHeader(status)
+	_, err := io.Copy(w, content)
+	return err
+}
+
+// Copy the reader in chunks, flushing each chunk when the response writer is an http.Flusher
+func flush(p contract.HttpProtocol, status int, reader io.Reader) error {
+	w := p.ResponseWriter()
+	if w.Header().Get(`Content-Type`) == `` {
+		w.Header().Set(`Content-Type`, `application/octet-stream`)
+	}
+	w.WriteHeader(status)
+
+	flusher, _ := w.(http.Flusher)
+	buf := make([]byte, streamChunkSize)
+	for {
+		n, err := reader.Read(buf)
+		if n > 0 {
+			if _, err := w.Write(buf[:n]); err != nil {
+				return err
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		// Stop streaming to the disconnected client
+		select {
+		case <-p.Request().Context().Done():
+			return p.Request().Context().Err()
+		default:
+		}
+	}
+}
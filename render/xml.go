@@ -0,0 +1,26 @@
+package render
+
+import (
+	xml2 "encoding/xml"
+	"github.com/firmeve/firmeve/kernel/contract"
+)
+
+type (
+	xml struct {
+	}
+)
+
+var (
+	XML = xml{}
+)
+
+func (xml) Render(protocol contract.Protocol, status int, v interface{}) error {
+	// A failed marshal leaves the status unwritten, so that the error can still be answered
+	bytes, err := xml2.Marshal(v)
+	if err != nil {
+		return err
+	}
+	writeHeader(protocol, status, `application/xml`)
+	_, err = protocol.Write(bytes)
+	return err
+}
@@ -0,0 +1,38 @@
+package render
+
+import (
+	"fmt"
+	"github.com/firmeve/firmeve/kernel/contract"
+	yaml2 "gopkg.in/yaml.v2"
+)
+
+type (
+	yaml struct {
+	}
+)
+
+var (
+	YAML = yaml{}
+)
+
+func (yaml) Render(protocol contract.Protocol, status int, v interface{}) error {
+	// A failed marshal leaves the status unwritten, so that the error can still be answered
+	bytes, err := marshalYAML(v)
+	if err != nil {
+		return err
+	}
+	writeHeader(protocol, status, `application/x-yaml`)
+	_, err = protocol.Write(bytes)
+	return err
+}
+
+// yaml.v2 panics on the values it can not encode, such as functions
+func marshalYAML(v interface{}) (bytes []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("yaml: %v", r)
+		}
+	}()
+
+	return yaml2.Marshal(v)
+}
@@ -0,0 +1,110 @@
+package tcp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+type (
+	// Frame the messages of a connection
+	Codec interface {
+		Decode(reader *bufio.Reader) ([]byte, error)
+
+		Encode(writer io.Writer, message []byte) error
+	}
+
+	// Newline delimited messages, a trailing \r is removed and the last line may miss the newline
+	lineCodec struct {
+		maxSize int
+	}
+
+	// Messages prefixed with the big endian uint32 length
+	lengthCodec struct {
+		maxSize uint32
+	}
+)
+
+var (
+	ErrMessageTooLarge = errors.New(`the message is too large`)
+)
+
+func NewLineCodec(maxSize int) Codec {
+	return &lineCodec{maxSize: maxSize}
+}
+
+func NewLengthCodec(maxSize uint32) Codec {
+	return &lengthCodec{maxSize: maxSize}
+}
+
+// The codec of the name, `line` or `length`
+func NewCodec(name string, maxSize int) (Codec, error) {
+	switch name {
+	case `line`:
+		return NewLineCodec(maxSize), nil
+	case `length`:
+		return NewLengthCodec(uint32(maxSize)), nil
+	}
+
+	return nil, fmt.Errorf("unsupported codec %s", name)
+}
+
+func (l *lineCodec) Decode(reader *bufio.Reader) ([]byte, error) {
+	message := make([]byte, 0)
+	for {
+		line, isPrefix, err := reader.ReadLine()
+		if err != nil {
+			return nil, err
+		}
+
+		message = append(message, line...)
+		if l.maxSize > 0 && len(message) > l.maxSize {
+			return nil, ErrMessageTooLarge
+		}
+		if !isPrefix {
+			return message, nil
+		}
+	}
+}
+
+func (l *lineCodec) Encode(writer io.Writer, message []byte) error {
+	if bytes.IndexByte(message, '\n') != -1 {
+		return fmt.Errorf("the line message contains a newline")
+	}
+
+	frame := make([]byte, len(message)+1)
+	copy(frame, message)
+	frame[len(message)] = '\n'
+
+	_, err := writer.Write(frame)
+	return err
+}
+
+func (l *lengthCodec) Decode(reader *bufio.Reader) ([]byte, error) {
+	var size uint32
+	if err := binary.Read(reader, binary.BigEndian, &size); err != nil {
+		return nil, err
+	}
+	if l.maxSize > 0 && size > l.maxSize {
+		return nil, ErrMessageTooLarge
+	}
+
+	message := make([]byte, size)
+	if _, err := io.ReadFull(reader, message); err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+func (l *lengthCodec) Encode(writer io.Writer, message []byte) error {
+	frame := make([]byte, 4+len(message))
+	binary.BigEndian.PutUint32(frame, uint32(len(message)))
+	copy(frame[4:], message)
+
+	_, err := writer.Write(frame)
+	return err
+}
@@ -0,0 +1,65 @@
+package tcp
+
+import (
+	"bufio"
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLineCodec(t *testing.T) {
+	codec := NewLineCodec(8)
+	buf := new(bytes.Buffer)
+	assert.Nil(t, codec.Encode(buf, []byte(`PING`)))
+	assert.NotNil(t, codec.Encode(buf, []byte("a\nb")))
+	assert.Equal(t, "PING\n", buf.String())
+
+	reader := bufio.NewReaderSize(strings.NewReader("GET a\r\n\nSET a 12345\nEND"), 16)
+	message, err := codec.Decode(reader)
+	assert.Nil(t, err)
+	assert.Equal(t, `GET a`, string(message))
+	message, err = codec.Decode(reader)
+	assert.Nil(t, err)
+	assert.Empty(t, message)
+	_, err = codec.Decode(reader)
+	assert.Equal(t, ErrMessageTooLarge, err)
+
+	// The last line may miss the newline
+	reader = bufio.NewReader(strings.NewReader(`END`))
+	message, err = NewLineCodec(0).Decode(reader)
+	assert.Nil(t, err)
+	assert.Equal(t, `END`, string(message))
+	_, err = NewLineCodec(0).Decode(reader)
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestLengthCodec(t *testing.T) {
+	codec := NewLengthCodec(8)
+	buf := new(bytes.Buffer)
+	assert.Nil(t, codec.Encode(buf, []byte("PING\n")))
+	assert.Equal(t, []byte{0, 0, 0, 5, 'P', 'I', 'N', 'G', '\n'}, buf.Bytes())
+
+	message, err := codec.Decode(bufio.NewReader(buf))
+	assert.Nil(t, err)
+	assert.Equal(t, "PING\n", string(message))
+
+	assert.Nil(t, codec.Encode(buf, []byte(`too large message`)))
+	_, err = codec.Decode(bufio.NewReader(buf))
+	assert.Equal(t, ErrMessageTooLarge, err)
+
+	_, err = codec.Decode(bufio.NewReader(bytes.NewReader([]byte{0, 0, 0, 5, 'P'})))
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+}
+
+func TestNewCodec(t *testing.T) {
+	codec, err := NewCodec(`line`, 1)
+	assert.Nil(t, err)
+	assert.IsType(t, &lineCodec{}, codec)
+	codec, err = NewCodec(`length`, 1)
+	assert.Nil(t, err)
+	assert.IsType(t, &lengthCodec{}, codec)
+	_, err = NewCodec(`json`, 1)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,95 @@
+package tcp
+
+import (
+	"context"
+	"fmt"
+	kernel2 "github.com/firmeve/firmeve/bootstrap"
+	"github.com/firmeve/firmeve/config"
+	"github.com/firmeve/firmeve/http"
+	"github.com/firmeve/firmeve/kernel"
+	"github.com/firmeve/firmeve/kernel/contract"
+	"github.com/spf13/cobra"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+type TCPCommand struct {
+	kernel.Command
+	command *cobra.Command
+}
+
+func (c *TCPCommand) Cmd() *cobra.Command {
+	if c.command == nil {
+		c.command = c.newCmd()
+	}
+
+	return c.command
+}
+
+func (c *TCPCommand) newCmd() *cobra.Command {
+	c.command = new(cobra.Command)
+	c.command.Use = "tcp:serve"
+	c.command.Short = "TCP server"
+	c.command.Flags().StringP("host", "H", ":9000", "TCP serve address, tcp://host:port or unix:///path/to/socket (default server config tcp.host)")
+	c.command.Flags().StringP("codec", "", "line", "Message framing, line or length")
+	c.command.Flags().DurationP("shutdown-timeout", "", 15*time.Second, "Graceful shutdown timeout")
+	c.command.Run = c.run
+	return c.command
+}
+
+func (c *TCPCommand) run(cmd *cobra.Command, args []string) {
+	// bootstrap
+	kernel2.BootFromCommand(c)
+
+	logger := c.Firmeve.Get(`logger`).(contract.Loggable)
+	serverConfig := NewServerConfig(c.Firmeve.Get(`config`).(*config.Config).Item(`server`)).MergeFlags(cmd)
+
+	srv, err := serverConfig.Server(c.Firmeve.Get(`tcp.router`).(*Router))
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("tcp server: %s\n", err))
+	}
+
+	event := c.Firmeve.Get(`event`).(contract.Event)
+	event.Dispatch(`tcp.starting`, map[string]interface{}{
+		`server`: srv,
+		`config`: serverConfig,
+	})
+
+	listener, err := http.Listen(serverConfig.Host, 0660)
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("listen: %s\n", err))
+	}
+
+	go func() {
+		logger.Info(fmt.Sprintf("Listening on %s://%s", listener.Addr().Network(), listener.Addr().String()))
+		if err := srv.Serve(listener); err != nil && err != ErrServerClosed {
+			logger.Fatal(fmt.Sprintf("serve: %s\n", err))
+		}
+	}()
+
+	event.Dispatch(`tcp.started`, map[string]interface{}{
+		`server`:   srv,
+		`config`:   serverConfig,
+		`listener`: listener,
+	})
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	signal.Stop(quit)
+
+	logger.Info("Shutdown TCP Server ...")
+	event.Dispatch(`tcp.stopping`, map[string]interface{}{
+		`server`: srv,
+		`config`: serverConfig,
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), serverConfig.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		logger.Error(fmt.Sprintf("tcp server shutdown: %s", err))
+	}
+
+	logger.Info("TCP Server exiting")
+}
@@ -0,0 +1,107 @@
+package tcp
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"strings"
+	"sync"
+)
+
+type (
+	// The protocol of a single message, `command payload`. Write replies a message framed by the codec
+	TCP struct {
+		*conn
+		command string
+		payload []byte
+		reader  *bytes.Reader
+	}
+
+	conn struct {
+		net.Conn
+		codec  Codec
+		ctx    context.Context
+		cancel context.CancelFunc
+		mutex  sync.Mutex
+	}
+)
+
+func NewTCP(c net.Conn, codec Codec, message []byte) *TCP {
+	return newTCP(newConn(c, codec), message)
+}
+
+// Split the message into the command name and the payload at the first space
+func newTCP(c *conn, message []byte) *TCP {
+	command, payload := message, []byte{}
+	if i := bytes.IndexByte(message, ' '); i != -1 {
+		command, payload = message[:i], message[i+1:]
+	}
+
+	return &TCP{
+		conn:    c,
+		command: string(command),
+		payload: payload,
+		reader:  bytes.NewReader(payload),
+	}
+}
+
+func (*TCP) Name() string {
+	return `tcp`
+}
+
+func (t *TCP) Read(p []byte) (int, error) {
+	return t.reader.Read(p)
+}
+
+func (t *TCP) Metadata() map[string][]string {
+	return map[string][]string{
+		`command`:     {t.command},
+		`remote_addr`: {t.RemoteAddr().String()},
+	}
+}
+
+// The payload after the command name
+func (t *TCP) Message() ([]byte, error) {
+	return t.payload, nil
+}
+
+// The space separated arguments of the payload
+func (t *TCP) Values() map[string][]string {
+	return map[string][]string{
+		`args`: t.Args(),
+	}
+}
+
+// Reply a message, safe for concurrent use by the goroutines of the connection
+func (t *TCP) Write(p []byte) (int, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if err := t.codec.Encode(t.Conn, p); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+func (t *TCP) Command() string {
+	return t.command
+}
+
+func (t *TCP) Args() []string {
+	return strings.Fields(string(t.payload))
+}
+
+// The context of the connection, canceled when the connection is closed
+func (t *TCP) Context() context.Context {
+	return t.ctx
+}
+
+func newConn(c net.Conn, codec Codec) *conn {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &conn{
+		Conn:   c,
+		codec:  codec,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
@@ -0,0 +1,22 @@
+package tcp
+
+import (
+	"github.com/firmeve/firmeve/container"
+	"github.com/firmeve/firmeve/kernel"
+)
+
+type Provider struct {
+	kernel.BaseProvider
+}
+
+func (p *Provider) Name() string {
+	return `tcp`
+}
+
+func (p *Provider) Register() {
+	p.Firmeve.Bind(`tcp.router`, New(p.Firmeve), container.WithShare(true))
+}
+
+func (p *Provider) Boot() {
+
+}
@@ -0,0 +1,99 @@
+package tcp
+
+import (
+	"fmt"
+	"github.com/firmeve/firmeve/kernel"
+	"github.com/firmeve/firmeve/kernel/contract"
+)
+
+type (
+	// Dispatch the messages to the handlers by the command name
+	Router struct {
+		Firmeve  contract.Application
+		routes   map[string]*Route
+		handlers []contract.ContextHandler
+		notFound contract.ContextHandler
+	}
+
+	Route struct {
+		name           string
+		beforeHandlers []contract.ContextHandler
+		afterHandlers  []contract.ContextHandler
+		handler        contract.ContextHandler
+	}
+)
+
+func New(firmeve contract.Application) *Router {
+	return &Router{
+		Firmeve:  firmeve,
+		routes:   make(map[string]*Route, 0),
+		handlers: make([]contract.ContextHandler, 0),
+		notFound: func(c contract.Context) {
+			c.Protocol().Write([]byte(fmt.Sprintf("ERR unknown command %s", c.Protocol().(*TCP).Command())))
+		},
+	}
+}
+
+// Register the handler of the command
+func (r *Router) Command(name string, handler contract.ContextHandler) *Route {
+	route := &Route{
+		name:           name,
+		beforeHandlers: make([]contract.ContextHandler, 0),
+		afterHandlers:  make([]contract.ContextHandler, 0),
+		handler:        handler,
+	}
+	r.routes[name] = route
+
+	return route
+}
+
+// Middleware running before the handlers of every command, including unknown commands
+func (r *Router) Use(handlers ...contract.ContextHandler) *Router {
+	r.handlers = append(r.handlers, handlers...)
+	return r
+}
+
+// Respond to unknown commands, the default replies `ERR unknown command <name>`
+func (r *Router) NotFound(handler contract.ContextHandler) *Router {
+	r.notFound = handler
+	return r
+}
+
+func (r *Router) Route(name string) *Route {
+	return r.routes[name]
+}
+
+// Run the middleware and the handlers of the command in a new context
+func (r *Router) Dispatch(protocol *TCP) {
+	handlers := make([]contract.ContextHandler, 0, len(r.handlers)+1)
+	handlers = append(handlers, r.handlers...)
+	if route, ok := r.routes[protocol.Command()]; ok {
+		handlers = append(handlers, route.Handlers()...)
+	} else {
+		handlers = append(handlers, r.notFound)
+	}
+
+	kernel.NewContext(r.Firmeve, protocol, handlers...).Next()
+}
+
+func (r *Route) Name() string {
+	return r.name
+}
+
+func (r *Route) Before(handlers ...contract.ContextHandler) *Route {
+	r.beforeHandlers = append(r.beforeHandlers, handlers...)
+	return r
+}
+
+func (r *Route) After(handlers ...contract.ContextHandler) *Route {
+	r.afterHandlers = append(r.afterHandlers, handlers...)
+	return r
+}
+
+func (r *Route) Handlers() []contract.ContextHandler {
+	handlers := make([]contract.ContextHandler, 0, len(r.beforeHandlers)+len(r.afterHandlers)+1)
+	handlers = append(handlers, r.beforeHandlers...)
+	handlers = append(handlers, r.handler)
+
+	return append(handlers, r.afterHandlers...)
+}
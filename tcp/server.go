@@ -0,0 +1,196 @@
+package tcp
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/firmeve/firmeve/kernel/contract"
+	"net"
+	"sync"
+	"time"
+)
+
+type (
+	Server struct {
+		router      *Router
+		codec       Codec
+		idleTimeout time.Duration
+		logger      contract.Loggable
+		listeners   map[net.Listener]struct{}
+		conns       map[*conn]struct{}
+		closed      bool
+		mutex       sync.Mutex
+		messages    sync.WaitGroup
+	}
+)
+
+var (
+	ErrServerClosed = errors.New(`tcp: server closed`)
+)
+
+// Create the server of the router, connections without messages are closed after the idle timeout, 0 means no timeout
+func NewServer(router *Router, codec Codec, idleTimeout time.Duration) *Server {
+	return &Server{
+		router:      router,
+		codec:       codec,
+		idleTimeout: idleTimeout,
+		logger:      router.Firmeve.Get(`logger`).(contract.Loggable),
+		listeners:   make(map[net.Listener]struct{}, 0),
+		conns:       make(map[*conn]struct{}, 0),
+	}
+}
+
+// Accept the connections of the listener, each connection is served by its own goroutine
+func (s *Server) Serve(listener net.Listener) error {
+	s.mutex.Lock()
+	if s.closed {
+		s.mutex.Unlock()
+		return ErrServerClosed
+	}
+	s.listeners[listener] = struct{}{}
+	s.mutex.Unlock()
+
+	var delay time.Duration
+	for {
+		c, err := listener.Accept()
+		if err != nil {
+			if s.isClosed() {
+				return ErrServerClosed
+			}
+
+			// Retry temporary errors such as too many open files like net/http
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				if delay = delay * 2; delay == 0 {
+					delay = 5 * time.Millisecond
+				} else if delay > time.Second {
+					delay = time.Second
+				}
+				s.logger.Error("tcp: accept error: " + err.Error())
+				time.Sleep(delay)
+				continue
+			}
+
+			return err
+		}
+
+		delay = 0
+		go s.serve(newConn(c, s.codec))
+	}
+}
+
+// Stop accepting connections, wait for the messages being handled and close the connections
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mutex.Lock()
+	s.closed = true
+	for listener := range s.listeners {
+		listener.Close()
+	}
+	// Wake up the connections waiting for messages
+	for c := range s.conns {
+		c.SetReadDeadline(time.Now())
+	}
+	s.mutex.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.messages.Wait()
+		close(done)
+	}()
+
+	var err error
+	select {
+	case <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	s.mutex.Lock()
+	for c := range s.conns {
+		c.Close()
+	}
+	s.mutex.Unlock()
+
+	return err
+}
+
+func (s *Server) serve(c *conn) {
+	if !s.track(c, true) {
+		c.Close()
+		return
+	}
+	defer func() {
+		s.track(c, false)
+		c.cancel()
+		c.Close()
+	}()
+
+	reader := bufio.NewReader(c)
+	for {
+		if s.idleTimeout > 0 {
+			c.SetReadDeadline(time.Now().Add(s.idleTimeout))
+		}
+
+		message, err := s.codec.Decode(reader)
+		if err != nil {
+			if err == ErrMessageTooLarge {
+				newTCP(c, nil).Write([]byte(`ERR ` + err.Error()))
+			}
+			return
+		} else if len(message) == 0 {
+			continue
+		}
+
+		if !s.dispatch(newTCP(c, message)) {
+			return
+		}
+	}
+}
+
+// Dispatch the message unless the server is shutting down
+func (s *Server) dispatch(protocol *TCP) bool {
+	s.mutex.Lock()
+	if s.closed {
+		s.mutex.Unlock()
+		return false
+	}
+	s.messages.Add(1)
+	s.mutex.Unlock()
+	defer s.messages.Done()
+
+	s.handle(protocol)
+	return true
+}
+
+// A panicking handler only fails its message
+func (s *Server) handle(protocol *TCP) {
+	defer func() {
+		if err := recover(); err != nil {
+			s.logger.Error(fmt.Sprintf("tcp: command %s panic: %v", protocol.Command(), err))
+			protocol.Write([]byte(`ERR internal error`))
+		}
+	}()
+
+	s.router.Dispatch(protocol)
+}
+
+func (s *Server) track(c *conn, add bool) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if add {
+		if s.closed {
+			return false
+		}
+		s.conns[c] = struct{}{}
+	} else {
+		delete(s.conns, c)
+	}
+
+	return true
+}
+
+func (s *Server) isClosed() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.closed
+}
@@ -0,0 +1,59 @@
+package tcp
+
+import (
+	"github.com/firmeve/firmeve/kernel/contract"
+	"github.com/spf13/cobra"
+	"time"
+)
+
+type (
+	ServerConfig struct {
+		Host            string
+		Codec           string
+		MaxMessageSize  int
+		IdleTimeout     time.Duration
+		ShutdownTimeout time.Duration
+	}
+)
+
+// Create a server config from the `tcp` node of the server config item
+func NewServerConfig(config contract.Configuration) *ServerConfig {
+	config.SetDefault(`tcp.host`, `:9000`)
+	config.SetDefault(`tcp.codec`, `line`)
+	config.SetDefault(`tcp.max_message_size`, 64<<10)
+	config.SetDefault(`tcp.shutdown_timeout`, 15*time.Second)
+
+	return &ServerConfig{
+		Host:            config.GetString(`tcp.host`),
+		Codec:           config.GetString(`tcp.codec`),
+		MaxMessageSize:  config.GetInt(`tcp.max_message_size`),
+		IdleTimeout:     config.GetDuration(`tcp.idle_timeout`),
+		ShutdownTimeout: config.GetDuration(`tcp.shutdown_timeout`),
+	}
+}
+
+// Override the config with the flags explicitly passed on the command line
+func (s *ServerConfig) MergeFlags(cmd *cobra.Command) *ServerConfig {
+	flags := cmd.Flags()
+	if flags.Changed(`host`) {
+		s.Host, _ = flags.GetString(`host`)
+	}
+	if flags.Changed(`codec`) {
+		s.Codec, _ = flags.GetString(`codec`)
+	}
+	if flags.Changed(`shutdown-timeout`) {
+		s.ShutdownTimeout, _ = flags.GetDuration(`shutdown-timeout`)
+	}
+
+	return s
+}
+
+// Create the server of the router with the codec of the config
+func (s *ServerConfig) Server(router *Router) (*Server, error) {
+	codec, err := NewCodec(s.Codec, s.MaxMessageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewServer(router, codec, s.IdleTimeout), nil
+}
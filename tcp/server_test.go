@@ -0,0 +1,125 @@
+package tcp
+
+import (
+	"bufio"
+	"context"
+	"github.com/firmeve/firmeve/config"
+	"github.com/firmeve/firmeve/kernel/contract"
+	logging "github.com/firmeve/firmeve/logger"
+	testing2 "github.com/firmeve/firmeve/testing"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type mockSetCommand struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+func newTestingRouter() *Router {
+	app := testing2.TestingModeFirmeve()
+	app.Bind(`logger`, logging.New(app.Get(`config`).(*config.Config).Item(`logging`)))
+
+	return New(app)
+}
+
+func newTestingServer(t *testing.T, router *Router, codec Codec) (*Server, net.Conn) {
+	listener, err := net.Listen(`tcp`, `127.0.0.1:0`)
+	assert.Nil(t, err)
+
+	server := NewServer(router, codec, time.Second)
+	go server.Serve(listener)
+
+	conn, err := net.Dial(`tcp`, listener.Addr().String())
+	assert.Nil(t, err)
+
+	return server, conn
+}
+
+func TestServer_Serve(t *testing.T) {
+	store := make(map[string]string)
+	router := newTestingRouter()
+	router.Use(func(c contract.Context) {
+		if c.Protocol().(*TCP).Command() == `AUTH` {
+			c.Protocol().Write([]byte(`ERR auth is disabled`))
+			return
+		}
+		c.Next()
+	})
+	router.Command(`SET`, func(c contract.Context) {
+		command := new(mockSetCommand)
+		if err := c.Bind(command); err != nil {
+			c.Protocol().Write([]byte(`ERR ` + err.Error()))
+			return
+		}
+		store[command.Key] = command.Value
+		c.Render(http.StatusOK, map[string]string{`status`: `OK`})
+		c.Next()
+	}).After(func(c contract.Context) {
+		c.Protocol().Write([]byte(`STORED`))
+	})
+	router.Command(`GET`, func(c contract.Context) {
+		args := c.Protocol().(*TCP).Args()
+		c.Protocol().Write([]byte(store[args[0]]))
+	})
+	router.Command(`PANIC`, func(c contract.Context) {
+		panic(`panic command`)
+	})
+
+	server, conn := newTestingServer(t, router, NewLineCodec(1024))
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	request := func(message string) string {
+		_, err := conn.Write([]byte(message + "\n"))
+		assert.Nil(t, err)
+		reply, err := reader.ReadString('\n')
+		assert.Nil(t, err)
+		return strings.TrimSuffix(reply, "\n")
+	}
+
+	assert.Equal(t, `{"status":"OK"}`, request(`SET {"key":"name","value":"firmeve"}`))
+	assert.Equal(t, `STORED`, request(``))
+	assert.Equal(t, `firmeve`, request(`GET name`))
+	assert.Equal(t, `ERR auth is disabled`, request(`AUTH secret`))
+	assert.Equal(t, `ERR unknown command DEL`, request(`DEL name`))
+	assert.Equal(t, `ERR internal error`, request(`PANIC`))
+	assert.Equal(t, `firmeve`, request(`GET name`))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.Nil(t, server.Shutdown(ctx))
+	_, err := reader.ReadString('\n')
+	assert.NotNil(t, err)
+	assert.Equal(t, ErrServerClosed, server.Serve(nil))
+}
+
+func TestServer_Serve_LengthCodec(t *testing.T) {
+	router := newTestingRouter()
+	router.Command(`ECHO`, func(c contract.Context) {
+		message, _ := c.Protocol().Message()
+		c.Protocol().Write(message)
+		assert.Nil(t, c.Err())
+	})
+
+	codec := NewLengthCodec(0)
+	server, conn := newTestingServer(t, router, NewLengthCodec(16))
+	defer server.Shutdown(context.Background())
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	assert.Nil(t, codec.Encode(conn, []byte("ECHO multi\nline")))
+	message, err := codec.Decode(reader)
+	assert.Nil(t, err)
+	assert.Equal(t, "multi\nline", string(message))
+
+	assert.Nil(t, codec.Encode(conn, []byte(`ECHO too large message`)))
+	message, err = codec.Decode(reader)
+	assert.Nil(t, err)
+	assert.Equal(t, `ERR the message is too large`, string(message))
+	_, err = codec.Decode(reader)
+	assert.NotNil(t, err)
+}
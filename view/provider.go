@@ -0,0 +1,48 @@
+package view
+
+import (
+	config2 "github.com/firmeve/firmeve/config"
+	"github.com/firmeve/firmeve/container"
+	"github.com/firmeve/firmeve/http"
+	"github.com/firmeve/firmeve/kernel"
+	"html/template"
+)
+
+type Provider struct {
+	kernel.BaseProvider
+}
+
+func (p *Provider) Name() string {
+	return `view`
+}
+
+func (p *Provider) Register() {
+	config := p.Firmeve.Get(`config`).(*config2.Config).Item(`view`)
+	config.SetDefault(`path`, `resources/views`)
+	config.SetDefault(`extension`, `.html`)
+	config.SetDefault(`shared`, []string{`layouts`, `partials`})
+	config.SetDefault(`asset.url`, `/`)
+	config.SetDefault(`csrf_field`, `_token`)
+
+	// Reload the templates in development unless the config says otherwise
+	reload := p.Firmeve.IsDevelopment()
+	if config.Exists(`reload`) && config.Get(`reload`) != nil {
+		reload = config.GetBool(`reload`)
+	}
+
+	p.Firmeve.Bind(`view`, New(config.GetString(`path`),
+		WithExtension(config.GetString(`extension`)),
+		WithSharedDirs(config.GetStringSlice(`shared`)...),
+		WithReload(reload),
+		WithAsset(config.GetString(`asset.url`), config.GetString(`asset.path`)),
+		WithCSRFField(config.GetString(`csrf_field`)),
+	), container.WithShare(true))
+}
+
+func (p *Provider) Boot() {
+	if p.Firmeve.Has(`http.router`) {
+		p.Firmeve.Get(`view`).(*View).Funcs(template.FuncMap{
+			`url`: p.Firmeve.Get(`http.router`).(*http.Router).URL,
+		})
+	}
+}
@@ -0,0 +1,306 @@
+package view
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"github.com/firmeve/firmeve/kernel"
+	"github.com/firmeve/firmeve/kernel/contract"
+	"github.com/firmeve/firmeve/support"
+	"html/template"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+type (
+	// The html/template views of a directory. Every page is parsed with the templates of the shared directories,
+	// so that a page renders a layout with {{ template "layouts/app" . }} and defines its blocks
+	View struct {
+		path      string
+		option    *option
+		funcs     template.FuncMap
+		shared    map[string]interface{}
+		templates map[string]*template.Template
+		versions  map[string]string
+		mutex     sync.RWMutex
+	}
+
+	option struct {
+		extension  string
+		sharedDirs []string
+		reload     bool
+		leftDelim  string
+		rightDelim string
+		assetURL   string
+		assetPath  string
+		csrfField  string
+	}
+)
+
+const (
+	sharedKey = `view.shared`
+)
+
+// The extension of the template files, the default is .html
+func WithExtension(extension string) support.Option {
+	return func(object support.Object) {
+		object.(*option).extension = extension
+	}
+}
+
+// The directories of the layouts and partials parsed with every page, the default is layouts and partials
+func WithSharedDirs(dirs ...string) support.Option {
+	return func(object support.Object) {
+		object.(*option).sharedDirs = dirs
+	}
+}
+
+// Parse the templates on every render, so that the changes show without restarting in development
+func WithReload(reload bool) support.Option {
+	return func(object support.Object) {
+		object.(*option).reload = reload
+	}
+}
+
+func WithDelims(left, right string) support.Option {
+	return func(object support.Object) {
+		object.(*option).leftDelim = left
+		object.(*option).rightDelim = right
+	}
+}
+
+// The url of the assets and the directory of the files hashed by the asset function
+func WithAsset(url, path string) support.Option {
+	return func(object support.Object) {
+		object.(*option).assetURL = url
+		object.(*option).assetPath = path
+	}
+}
+
+// The name of the hidden input of the csrfField function, the default is _token
+func WithCSRFField(name string) support.Option {
+	return func(object support.Object) {
+		object.(*option).csrfField = name
+	}
+}
+
+func New(path string, options ...support.Option) *View {
+	v := &View{
+		path: path,
+		option: support.ApplyOption(&option{
+			extension:  `.html`,
+			sharedDirs: []string{`layouts`, `partials`},
+			assetURL:   `/`,
+			csrfField:  `_token`,
+		}, options...).(*option),
+		shared:    make(map[string]interface{}, 0),
+		templates: make(map[string]*template.Template, 0),
+		versions:  make(map[string]string, 0),
+	}
+	v.funcs = template.FuncMap{
+		`asset`:     v.Asset,
+		`csrfField`: v.CSRFField,
+	}
+
+	return v
+}
+
+// Add the functions of the templates, such as url of the route provider
+func (v *View) Funcs(funcs template.FuncMap) *View {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	for name, fn := range funcs {
+		v.funcs[name] = fn
+	}
+	// The functions are bound at parsing
+	v.templates = make(map[string]*template.Template, 0)
+
+	return v
+}
+
+// Share the data with every template, the keys of the render data take precedence
+func (v *View) Share(key string, value interface{}) *View {
+	v.mutex.Lock()
+	v.shared[key] = value
+	v.mutex.Unlock()
+
+	return v
+}
+
+// Render the page of the name, e.g. users/index is the file users/index.html of the directory.
+// The shared data is merged into map data, other data is rendered as it is
+func (v *View) Render(c contract.Context, w io.Writer, name string, data interface{}) error {
+	t, err := v.template(name)
+	if err != nil {
+		return err
+	}
+
+	return t.ExecuteTemplate(w, name, v.data(c, data))
+}
+
+// The url of the asset versioned by the hash of the content, e.g. /assets/css/app.css?v=5d41402a
+func (v *View) Asset(path string) string {
+	path = strings.TrimLeft(path, `/`)
+	url := strings.TrimRight(v.option.assetURL, `/`) + `/` + path
+	if version := v.assetVersion(path); version != `` {
+		url += `?v=` + version
+	}
+
+	return url
+}
+
+// The hidden input of the csrf token, e.g. {{ csrfField .csrf_token }}
+func (v *View) CSRFField(token string) template.HTML {
+	return template.HTML(`<input type="hidden" name="` + template.HTMLEscapeString(v.option.csrfField) +
+		`" value="` + template.HTMLEscapeString(token) + `">`)
+}
+
+func (v *View) template(name string) (*template.Template, error) {
+	if !v.option.reload {
+		v.mutex.RLock()
+		t, ok := v.templates[name]
+		v.mutex.RUnlock()
+		if ok {
+			return t, nil
+		}
+	}
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	t, err := v.parse(name)
+	if err != nil {
+		return nil, err
+	}
+	if !v.option.reload {
+		v.templates[name] = t
+	}
+
+	return t, nil
+}
+
+// Parse the shared templates before the page, so that the blocks of the layouts are replaced by the page
+func (v *View) parse(name string) (*template.Template, error) {
+	file, err := v.file(name)
+	if err != nil {
+		return nil, err
+	}
+
+	t := template.New(``).Delims(v.option.leftDelim, v.option.rightDelim).Funcs(v.funcs)
+	for _, dir := range v.option.sharedDirs {
+		root := filepath.Join(v.path, filepath.FromSlash(dir))
+		if _, err := os.Stat(root); os.IsNotExist(err) {
+			continue
+		}
+
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() || filepath.Ext(path) != v.option.extension {
+				return err
+			}
+
+			return v.parseFile(t, path)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := v.parseFile(t, file); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (v *View) parseFile(t *template.Template, file string) error {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	name, err := filepath.Rel(v.path, file)
+	if err != nil {
+		return err
+	}
+	_, err = t.New(strings.TrimSuffix(filepath.ToSlash(name), v.option.extension)).Parse(string(content))
+
+	return err
+}
+
+func (v *View) file(name string) (string, error) {
+	root, err := filepath.Abs(v.path)
+	if err != nil {
+		return ``, err
+	}
+
+	file := filepath.Join(root, filepath.FromSlash(name)+v.option.extension)
+	if !strings.HasPrefix(file, root+string(filepath.Separator)) {
+		return ``, kernel.Errorf("the view %s is out of the directory", name)
+	}
+
+	return filepath.Join(v.path, filepath.FromSlash(name)+v.option.extension), nil
+}
+
+func (v *View) data(c contract.Context, data interface{}) interface{} {
+	values, ok := data.(map[string]interface{})
+	if !ok && data != nil {
+		return data
+	}
+
+	merged := make(map[string]interface{}, len(v.shared)+len(values))
+	v.mutex.RLock()
+	for key, value := range v.shared {
+		merged[key] = value
+	}
+	v.mutex.RUnlock()
+	if c != nil {
+		if entity := c.Entity(sharedKey); entity != nil {
+			for key, value := range entity.Value.(map[string]interface{}) {
+				merged[key] = value
+			}
+		}
+	}
+	for key, value := range values {
+		merged[key] = value
+	}
+
+	return merged
+}
+
+func (v *View) assetVersion(path string) string {
+	if v.option.assetPath == `` {
+		return ``
+	}
+
+	v.mutex.RLock()
+	version, ok := v.versions[path]
+	v.mutex.RUnlock()
+	if ok && !v.option.reload {
+		return version
+	}
+
+	content, err := ioutil.ReadFile(filepath.Join(v.option.assetPath, filepath.FromSlash(path)))
+	if err == nil {
+		sum := md5.Sum(content)
+		version = hex.EncodeToString(sum[:])[:8]
+	}
+
+	v.mutex.Lock()
+	v.versions[path] = version
+	v.mutex.Unlock()
+
+	return version
+}
+
+// Share the data with the views rendered by the context, e.g. the current user shared by a middleware
+func Share(c contract.Context, key string, value interface{}) {
+	if entity := c.Entity(sharedKey); entity != nil {
+		entity.Value.(map[string]interface{})[key] = value
+		return
+	}
+
+	c.AddEntity(sharedKey, map[string]interface{}{key: value})
+}
@@ -0,0 +1,117 @@
+package view
+
+import (
+	"bytes"
+	"github.com/firmeve/firmeve/config"
+	"github.com/firmeve/firmeve/event"
+	"github.com/firmeve/firmeve/http"
+	"github.com/firmeve/firmeve/kernel"
+	"github.com/firmeve/firmeve/kernel/contract"
+	"github.com/firmeve/firmeve/support"
+	"github.com/firmeve/firmeve/support/path"
+	testing2 "github.com/firmeve/firmeve/testing"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	net_http "net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type mockUser struct {
+	ID   int
+	Name string
+}
+
+func newTestingView(options ...support.Option) *View {
+	return New(path.RunRelative(`../testdata/views`), append([]support.Option{
+		WithAsset(`/assets`, path.RunRelative(`../testdata/assets`)),
+	}, options...)...)
+}
+
+func TestView_Render(t *testing.T) {
+	v := newTestingView().Share(`app`, `firmeve`)
+	buf := new(bytes.Buffer)
+	assert.Nil(t, v.Render(nil, buf, `users/index`, map[string]interface{}{
+		`users`:      []string{`simon`, `<b>`},
+		`csrf_token`: `token"1`,
+	}))
+
+	html := buf.String()
+	assert.Contains(t, html, `<title>Users</title>`)
+	assert.Contains(t, html, `<link href="/assets/css/app.css?v=`)
+	assert.Contains(t, html, `<nav>firmeve</nav>`)
+	assert.Contains(t, html, `<input type="hidden" name="_token" value="token&#34;1">`)
+	assert.Contains(t, html, `<p>simon</p><p>&lt;b&gt;</p>`)
+
+	assert.NotNil(t, v.Render(nil, buf, `users/missing`, nil))
+	assert.NotNil(t, v.Render(nil, buf, `../config/app`, nil))
+	// url is registered by the provider with the router
+	assert.NotNil(t, v.Render(nil, buf, `users/show`, mockUser{ID: 1}))
+}
+
+func TestView_Asset(t *testing.T) {
+	v := newTestingView()
+	assert.Regexp(t, `^/assets/css/app\.css\?v=[0-9a-f]{8}$`, v.Asset(`/css/app.css`))
+	assert.Equal(t, `/assets/js/missing.js`, v.Asset(`js/missing.js`))
+	assert.Equal(t, `/css/app.css`, New(``).Asset(`css/app.css`))
+}
+
+func TestView_Reload(t *testing.T) {
+	dir, err := ioutil.TempDir(``, `firmeve`)
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, `index.tpl`)
+
+	for _, reload := range []bool{false, true} {
+		v := New(dir, WithExtension(`.tpl`), WithDelims(`[[`, `]]`), WithReload(reload))
+		assert.Nil(t, ioutil.WriteFile(file, []byte(`v1 [[ .name ]]`), 0644))
+		buf := new(bytes.Buffer)
+		assert.Nil(t, v.Render(nil, buf, `index`, map[string]interface{}{`name`: `firmeve`}))
+		assert.Equal(t, `v1 firmeve`, buf.String())
+
+		assert.Nil(t, ioutil.WriteFile(file, []byte(`v2`), 0644))
+		buf.Reset()
+		assert.Nil(t, v.Render(nil, buf, `index`, nil))
+		if reload {
+			assert.Equal(t, `v2`, buf.String())
+		} else {
+			assert.Equal(t, `v1 `, strings.Replace(buf.String(), `<no value>`, ``, 1))
+		}
+	}
+}
+
+func TestProvider(t *testing.T) {
+	app := testing2.TestingModeFirmeve()
+	app.Bind(`event`, event.New())
+	app.Get(`config`).(*config.Config).Item(`view`).Set(`path`, path.RunRelative(`../testdata/views`))
+	router := http.New(app)
+	router.GET(`/users/:id`, func(c contract.Context) {
+		Share(c, `app`, `shared`)
+		Share(c, `user`, c.Param(`id`))
+		assert.Nil(t, c.View(net_http.StatusOK, `users/show`, mockUser{ID: 1, Name: `simon`}))
+		c.Next()
+	}).Name(`users.show`)
+	router.GET(`/`, func(c contract.Context) {
+		Share(c, `app`, `shared`)
+		assert.Nil(t, c.View(net_http.StatusOK, `users/index`, map[string]interface{}{`csrf_token`: `token`}))
+		c.Next()
+	})
+	app.Bind(`http.router`, router)
+
+	provider := &Provider{BaseProvider: kernel.BaseProvider{Firmeve: app}}
+	provider.Register()
+	provider.Boot()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(net_http.MethodGet, `/users/1`, nil))
+	assert.Equal(t, `text/html; charset=utf-8`, w.Header().Get(`Content-Type`))
+	assert.Equal(t, "<a href=\"/users/1\">simon</a>\n", w.Body.String())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(net_http.MethodGet, `/`, nil))
+	assert.Contains(t, w.Body.String(), `<nav>shared</nav>`)
+	assert.Contains(t, w.Body.String(), `<title>Users</title>`)
+}